@@ -10,51 +10,81 @@ import (
 // provides integers, the mean will be computed using integer
 // division and will therefore be somewhat inaccurate.
 func Mean[T Number]() Applier[T, T] {
-	n := 0
-	var total T
+	// The accumulator is the count along with the total.
+	step := accumulate(Pair[int, T]{}, func(acc Pair[int, T], v T) (Pair[int, T], error) {
+		return Pair[int, T]{acc.Left + 1, acc.Right + v}, nil
+	})
+
 	return func(t T) (T, error) {
-		n++
-		total += t
-		return total / T(n), nil
+		acc, err := step(t)
+		return acc.Right / T(acc.Left), err
 	}
 }
 
 // Max produces the maximum value seen so far.
 func Max[T cmp.Ordered]() Applier[T, T] {
-	maximum := *new(T)
-	first := true
-
-	return func(t T) (T, error) {
-		if t > maximum || first {
-			maximum = t
-			first = false
+	step := accumulate(Option[T]{}, func(maximum Option[T], t T) (Option[T], error) {
+		if !maximum.Valid || t > maximum.Value {
+			return Option[T]{t, true}, nil
 		}
 
 		return maximum, nil
+	})
+
+	return func(t T) (T, error) {
+		maximum, err := step(t)
+		return maximum.Value, err
 	}
 }
 
 // Min produces the minimum value seen so far.
 func Min[T cmp.Ordered]() Applier[T, T] {
-	minimum := *new(T)
-	first := true
-
-	return func(t T) (T, error) {
-		if t < minimum || first {
-			minimum = t
-			first = false
+	step := accumulate(Option[T]{}, func(minimum Option[T], t T) (Option[T], error) {
+		if !minimum.Valid || t < minimum.Value {
+			return Option[T]{t, true}, nil
 		}
 
 		return minimum, nil
+	})
+
+	return func(t T) (T, error) {
+		minimum, err := step(t)
+		return minimum.Value, err
 	}
 }
 
 // Sum produces an iterator that provides a moving sum of the
 // values from the underlying iterator.
 func Sum[T Number]() Applier[T, T] {
-	var total T
-	return func(v T) (T, error) {
-		total += v
+	var zero T
+	return accumulate(zero, func(total T, v T) (T, error) {
+		return total + v, nil
+	})
+}
+
+// WindowMean produces the mean of each window of values, such as
+// those produced by Sliding. As with Mean, integer windows use
+// integer division. The mean of an empty window is zero.
+func WindowMean[T Number]() Applier[[]T, T] {
+	return func(vs []T) (T, error) {
+		if len(vs) == 0 {
+			return 0, nil
+		}
+
+		total, _ := WindowSum[T]()(vs)
+		return total / T(len(vs)), nil
+	}
+}
+
+// WindowSum produces the sum of each window of values, such as
+// those produced by Sliding.
+func WindowSum[T Number]() Applier[[]T, T] {
+	return func(vs []T) (T, error) {
+		var total T
+		for _, v := range vs {
+			total += v
+		}
+
 		return total, nil
 	}
 }
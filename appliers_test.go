@@ -1,6 +1,8 @@
 package funky
 
 import (
+	"slices"
+	"sync"
 	"testing"
 
 	"github.com/alecthomas/assert/v2"
@@ -27,3 +29,64 @@ func TestMean(t *testing.T) {
 		assert.Equal(t, 3, v3)
 	})
 }
+
+func TestMax(t *testing.T) {
+	t.Run("should track the maximum", func(t *testing.T) {
+		it := Apply(FromVals(-3, -5, -1, -2), Max[int]())
+		assertValues(t, it, []int{-3, -3, -1, -1}, true)
+	})
+}
+
+func TestMin(t *testing.T) {
+	t.Run("should track the minimum", func(t *testing.T) {
+		it := Apply(FromVals(3, 5, 1, 2), Min[int]())
+		assertValues(t, it, []int{3, 3, 1, 1}, true)
+	})
+}
+
+func TestSum(t *testing.T) {
+	t.Run("should be safe to use from several goroutines", func(t *testing.T) {
+		ones := make([]Elem[int], 100)
+		for i := range ones {
+			ones[i].val = 1
+		}
+
+		it := Apply(fromElems(ones), Sum[int]())
+
+		var wg sync.WaitGroup
+		var lock sync.Mutex
+		totals := make([]int, 0, 100)
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				for v := range it.ToSeq() {
+					lock.Lock()
+					totals = append(totals, v)
+					lock.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		slices.Sort(totals)
+		for i, total := range totals {
+			assert.Equal(t, i+1, total)
+		}
+	})
+}
+
+func TestWindowMean(t *testing.T) {
+	t.Run("should average each window", func(t *testing.T) {
+		it := Apply(Sliding(FromVals(1.0, 2.0, 3.0, 6.0), 2, 1), WindowMean[float64]())
+		assertValues(t, it, []float64{1.5, 2.5, 4.5}, true)
+	})
+}
+
+func TestWindowSum(t *testing.T) {
+	t.Run("should sum each window", func(t *testing.T) {
+		it := Apply(Sliding(makeFinite(5), 3, 1), WindowSum[int]())
+		assertValues(t, it, []int{3, 6, 9}, true)
+	})
+}
@@ -1,6 +1,10 @@
 package funky
 
-import "fmt"
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
 
 // An Applier is a function that can be used with Apply.
 type Applier[I, O any] func(I) (O, error)
@@ -18,16 +22,85 @@ func Apply[I, O any](it *Iter[I], f Applier[I, O]) *Iter[O] {
 				return DoneElem[O]()
 			}
 
-			if inElem.err != nil {
-				return ErrElem[O](fmt.Errorf("apply input error: %w", inElem.err))
-			}
+			return applyElem(inElem, f)
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// FlatMap transforms each input value into an iterator of output
+// values, and produces all of the values from each of those, in
+// order. This allows a single input to produce any number of
+// outputs, including none at all. Each inner iterator is closed
+// once it has been exhausted, or when the outer iterator is
+// closed. A nil inner iterator is treated as an empty one.
+//
+// For example (in pseudocode):
+//
+//	FlatMap({1, 2, 3}, x -> Take(Infinite(x), x)) -> {1, 2, 2, 3, 3, 3}
+func FlatMap[I, O any](it *Iter[I], f func(I) (*Iter[O], error)) *Iter[O] {
+	// The inner iterator is only replaced while holding the lock,
+	// but close needs to get at it without waiting on a call to
+	// Next that may be blocked inside of it.
+	var inner atomic.Pointer[Iter[O]]
+	var lock sync.Mutex
+
+	return &Iter[O]{
+		next: func() (Elem[O], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			for {
+				if current := inner.Load(); current != nil {
+					elem, valid := current.Next()
+					if valid {
+						return elem, true
+					}
 
-			outVal, err := f(inElem.val)
-			if err != nil {
-				return ErrElem[O](err)
+					current.Close()
+					inner.Store(nil)
+				}
+
+				inElem, valid := it.Next()
+				if !valid {
+					return DoneElem[O]()
+				}
+
+				if inElem.err != nil {
+					return ErrElem[O](fmt.Errorf("flat map input error: %w", inElem.err))
+				}
+
+				next, err := f(inElem.val)
+				if err != nil {
+					return ErrElem[O](err)
+				}
+
+				inner.Store(next)
 			}
+		},
+		close: func() {
+			it.Close()
 
-			return ValElem(outVal)
+			if current := inner.Load(); current != nil {
+				current.Close()
+			}
 		},
 	}
 }
+
+// applyElem transforms a single element the way Apply does, passing
+// along any error it already carries.
+func applyElem[I, O any](inElem Elem[I], f Applier[I, O]) (Elem[O], bool) {
+	if inElem.err != nil {
+		return ErrElem[O](fmt.Errorf("apply input error: %w", inElem.err))
+	}
+
+	outVal, err := f(inElem.val)
+	if err != nil {
+		return ErrElem[O](err)
+	}
+
+	return ValElem(outVal)
+}
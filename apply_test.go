@@ -1,6 +1,7 @@
 package funky
 
 import (
+	"errors"
 	"strconv"
 	"testing"
 
@@ -64,13 +65,60 @@ func TestApply(t *testing.T) {
 		assert.NoError(t, elem.err)
 		assert.Equal(t, "", elem.val)
 
-		srcElem, srcValid := it.Next()
-		assert.True(t, srcValid)
-		assert.NoError(t, srcElem.err)
-		assert.Equal(t, 1, srcElem.val)
+		_, srcValid := it.Next()
+		assert.False(t, srcValid)
 	})
 
 	t.Run("should handle applier error", func(t *testing.T) {
 		//
 	})
 }
+
+func TestFlatMap(t *testing.T) {
+	t.Run("should produce every inner value", func(t *testing.T) {
+		it := FlatMap(FromVals(1, 0, 2), func(n int) (*Iter[int], error) {
+			return Take(makeConstant(n), uint64(n)), nil
+		})
+		assertValues(t, it, []int{1, 2, 2}, true)
+	})
+
+	t.Run("should pass along errors", func(t *testing.T) {
+		it := FlatMap(makeMixed(), func(n int) (*Iter[int], error) {
+			if n == 2 {
+				return nil, errors.New("error")
+			}
+
+			return FromVals(n), nil
+		})
+		assertValues(t, it, []int{1}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		elem, valid = it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []int{}, true)
+	})
+
+	t.Run("should close inner iterators", func(t *testing.T) {
+		var inners []*Iter[int]
+		src := makeFinite(3)
+		it := FlatMap(src, func(n int) (*Iter[int], error) {
+			inner := makeInfinite()
+			inners = append(inners, inner)
+			return Take(inner, 1), nil
+		})
+		assertValues(t, it, []int{0, 0}, false)
+
+		// The first inner iterator was exhausted, the second is
+		// still in use until we close the outer one.
+		assertClosed(t, inners[0])
+
+		it.Close()
+		assertClosed(t, inners[1])
+		assertClosed(t, src)
+	})
+}
@@ -9,104 +9,121 @@ import "sync"
 // and evaluation is single-threaded, though it takes place off the
 // calling goroutine. The buffer begins filling immediately.
 //
+// Closing the iterator discards any values still in the buffer, so
+// it never waits on a reader. The source is closed in the
+// background, since the goroutine filling the buffer may be waiting
+// on it.
+//
 // Example: remoteFiles := Buffer(httpRequests, 10)
 func Buffer[T any](it *Iter[T], size uint32) *Iter[T] {
-	// Elements from the source iterator, will be closed when
-	// the Iter stops, but may still contain previously generated
-	// values which can still be read out with Next(). These
-	// values are preserved so a buffered pipeline doesn't "leak".
+	// Elements from the source iterator, will be closed when the
+	// source is exhausted. Anything left in it when the iterator is
+	// closed is thrown away.
 	elements := make(chan Elem[T], size)
 
 	// Requests for new elements to be buffered after the buffer
 	// has been filled initially. These correspond to calls to
 	// Next(), first trigger a back-fill of the buffer, then wait
-	// for the first available ready element.
-	requests := make(chan interface{}, 1)
-
-	// We use this to keep Close() from returning until all
-	// previously buffered values have been retrieved with Next().
-	var elemGroup sync.WaitGroup
-
-	loadOne := func() {
-		elem, valid := it.Next()
+	// for the first available ready element. There can never be
+	// more outstanding requests than there is room in the buffer,
+	// so sending a request never blocks.
+	requests := make(chan interface{}, size)
+
+	// Closed when the iterator is closed so that the goroutine
+	// below, and any callers waiting on it, can give up.
+	stop := make(chan interface{})
+	var stopOnce sync.Once
+
+	loadOne := func() bool {
+		elem, valid := catchPanic(it.Next)
 		if !valid {
-			return
+			return false
+		}
+
+		// There is always room for the element, but if we've
+		// been stopped in the meantime, nobody wants it.
+		select {
+		case <-stop:
+			return false
+		default:
 		}
 
-		elemGroup.Add(1)
 		elements <- elem
+		return true
 	}
 
 	// Fetch elements, one at a time, from the source iterator
 	// in a goroutine so we don't block creating the buffered
 	// iterator itself. This is the only place we can add to
-	// the elements channel.
+	// the elements channel. Once the source is exhausted, we
+	// close the elements channel so that callers waiting on
+	// it know there's nothing more coming.
 	go func() {
 		defer close(elements)
 
 		for i := uint32(0); i < size; i++ {
-			// We don't rely on accessing this here, but we do
-			// want to bail if we're closed before the initial
-			// buffer is even filled.
-			if requests == nil {
+			// Bail if we're closed before the initial buffer
+			// is even filled.
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			if !loadOne() {
 				return
 			}
-			loadOne()
 		}
 
-		for _ = range requests {
-			loadOne()
+		for {
+			select {
+			case <-requests:
+				if !loadOne() {
+					return
+				}
+			case <-stop:
+				return
+			}
 		}
 	}()
 
-	// We mess around with the requests channel in two places,
-	// in next() and stop(), so we guard it to prevent race
-	// conditions.
-	var reqLock sync.Mutex
-
 	return &Iter[T]{
 		next: func() (Elem[T], bool) {
-			elem, more := <-elements
-			if !more {
-				return DoneElem[T]()
-			}
-
-			// We took an element, so decrement the wait group
-			// to move Close() closer to being able to return.
-			elemGroup.Done()
-
-			// Ask for another element to be buffered
-			// since we just took one, but only if we
-			// haven't been stopped.
-			go func() {
-				if requests == nil {
-					return
+			select {
+			case elem, more := <-elements:
+				if !more {
+					return DoneElem[T]()
 				}
 
-				reqLock.Lock()
-				defer reqLock.Unlock()
+				// Ask for another element to be buffered since we
+				// just took one.
+				requests <- nil
 
-				if requests != nil {
-					requests <- nil
-				}
-			}()
-
-			return elem, true
+				return rethrow(elem, true)
+			case <-stop:
+				return DoneElem[T]()
+			}
 		},
 		close: func() {
-			if requests != nil {
-				reqLock.Lock()
-				defer reqLock.Unlock()
-
-				if requests != nil {
-					close(requests)
-					requests = nil
+			stopOnce.Do(func() {
+				close(stop)
+			})
+
+			// Nobody is going to read what's left, so we let it
+			// go rather than wait for a reader.
+			for drained := false; !drained; {
+				select {
+				case <-elements:
+				default:
+					drained = true
 				}
 			}
 
-			// Wait for all outstanding elements to be retrieved
-			// with Next().
-			elemGroup.Wait()
+			// Closing the source frees up the goroutine above if
+			// it is waiting on a value, but the source can't
+			// finish closing until that wait is over, so we don't
+			// wait for it.
+			go it.Close()
 		},
 	}
 }
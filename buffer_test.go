@@ -46,13 +46,49 @@ func TestBuffer(t *testing.T) {
 		b := Buffer(src, uint32(size))
 		<-done
 
-		// TODO: Add a version that reads two values, spins off a reader that waits until Close has been called, then reads the last
+		// Taking a value asks for another, which gets stuck in the
+		// callback above, so the source is busy when we close.
+		assertValues(t, b, []int{0}, false)
 
-		go assertValues(t, b, []int{0, 1, 2}, true)
+		b.Close()
+
+		_, valid := b.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should end when the source is exhausted", func(t *testing.T) {
+		b := Buffer(makeFinite(3), 2)
+		assertValues(t, b, []int{0, 1, 2}, true)
+
+		_, valid := b.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		src := makeInfinite()
+		b := Buffer(src, 2)
+		assertValues(t, b, []int{0}, false)
+
+		b.Close()
+		<-src.Done()
+	})
+
+	t.Run("should close without a reader when half read", func(t *testing.T) {
+		b := Buffer(makeFinite(100), 10)
+		assertValues(t, b, []int{0, 1}, false)
 
 		b.Close()
 
 		_, valid := b.Next()
 		assert.False(t, valid)
 	})
+
+	t.Run("should close without a reader inside a pipeline", func(t *testing.T) {
+		src := makeFinite(100)
+		taken := Take(Buffer(src, 10), 2)
+		assertValues(t, taken, []int{0, 1}, true)
+
+		taken.Close()
+		<-src.Done()
+	})
 }
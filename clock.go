@@ -0,0 +1,26 @@
+package funky
+
+import "time"
+
+// A Clock tells the time and waits for it to pass. Operators that
+// wait, such as those that retry or limit their rate, accept one so
+// that tests can control time instead of sleeping.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// After returns a channel that receives the current time once
+	// the duration has passed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is a Clock that uses the time package.
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
@@ -0,0 +1,60 @@
+package funky
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"io"
+)
+
+// A Codec serializes values so that they can be written to, and
+// read back from, a stream of bytes, such as a temporary file used
+// to hold values that won't fit in memory.
+type Codec[T any] interface {
+	// Encoder returns a function that writes values to w.
+	Encoder(w io.Writer) func(T) error
+
+	// Decoder returns a function that reads values from r, in the
+	// order they were written. It returns io.EOF once there are no
+	// values left.
+	Decoder(r io.Reader) func() (T, error)
+}
+
+// GobCodec serializes values using encoding/gob. It is fast and
+// compact, but it only handles exported struct fields.
+type GobCodec[T any] struct{}
+
+func (GobCodec[T]) Encoder(w io.Writer) func(T) error {
+	enc := gob.NewEncoder(w)
+	return func(v T) error {
+		return enc.Encode(v)
+	}
+}
+
+func (GobCodec[T]) Decoder(r io.Reader) func() (T, error) {
+	dec := gob.NewDecoder(r)
+	return func() (T, error) {
+		var v T
+		err := dec.Decode(&v)
+		return v, err
+	}
+}
+
+// JSONCodec serializes values using encoding/json, one value per
+// line, which makes spilled values easy to inspect.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encoder(w io.Writer) func(T) error {
+	enc := json.NewEncoder(w)
+	return func(v T) error {
+		return enc.Encode(v)
+	}
+}
+
+func (JSONCodec[T]) Decoder(r io.Reader) func() (T, error) {
+	dec := json.NewDecoder(r)
+	return func() (T, error) {
+		var v T
+		err := dec.Decode(&v)
+		return v, err
+	}
+}
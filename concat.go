@@ -60,7 +60,9 @@ func Concat[T any](its ...*Iter[T]) *Iter[T] {
 			}
 		},
 		close: func() {
-			its = nil
+			for _, it := range its {
+				it.Close()
+			}
 		},
 	}
 }
@@ -33,4 +33,16 @@ func TestConcat(t *testing.T) {
 		assert.True(t, valid)
 		assert.Error(t, elem.err)
 	})
+
+	t.Run("should close every source", func(t *testing.T) {
+		first := FromVals(1, 2)
+		second := FromVals(3, 4)
+
+		vals0 := Concat(first, second)
+		assertValues(t, vals0, []int{1}, false)
+		vals0.Close()
+
+		assertClosed(t, first)
+		assertClosed(t, second)
+	})
 }
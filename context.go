@@ -0,0 +1,66 @@
+package funky
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// WithContext binds an iterator to a context. Once the context is
+// cancelled, or its deadline passes, the iterator produces the
+// context's error as a final element and then ends.
+//
+// The source iterator is closed at the same moment, which, in turn,
+// closes everything upstream of it, so a WithContext at the end of
+// a pipeline will stop the whole thing. A call to Next that is
+// waiting on the source when the context ends returns right away
+// rather than waiting for the source to produce a value.
+//
+// Example: lines := WithContext(ctx, Apply(urls, fetch))
+func WithContext[T any](ctx context.Context, it *Iter[T]) *Iter[T] {
+	stop := context.AfterFunc(ctx, it.Close)
+
+	// Only the first call after the context ends gets to see its
+	// error, after that, the iterator is simply exhausted.
+	var reported atomic.Bool
+	cancelled := func() (Elem[T], bool) {
+		if reported.CompareAndSwap(false, true) {
+			return ErrElem[T](ctx.Err())
+		}
+
+		return DoneElem[T]()
+	}
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			if ctx.Err() != nil {
+				return cancelled()
+			}
+
+			// A context that can never be cancelled doesn't need
+			// the extra goroutine.
+			if ctx.Done() == nil {
+				return it.Next()
+			}
+
+			// Buffered so that the goroutine can finish up even
+			// if we've given up on it.
+			result := make(chan Pair[Elem[T], bool], 1)
+
+			go func() {
+				elem, valid := catchPanic(it.Next)
+				result <- Pair[Elem[T], bool]{elem, valid}
+			}()
+
+			select {
+			case r := <-result:
+				return rethrow(r.Left, r.Right)
+			case <-ctx.Done():
+				return cancelled()
+			}
+		},
+		close: func() {
+			stop()
+			it.Close()
+		},
+	}
+}
@@ -0,0 +1,89 @@
+package funky
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestWithContext(t *testing.T) {
+	t.Run("should produce values until cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		it := WithContext(ctx, makeInfinite())
+		assertValues(t, it, []int{0, 1}, false)
+
+		cancel()
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.IsError(t, elem.err, context.Canceled)
+
+		_, valid = it.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should close a buffer that nobody has read", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		src := makeFinite(100)
+		buffered := Buffer(src, 10)
+		it := WithContext(ctx, buffered)
+		assertValues(t, it, []int{0}, false)
+
+		cancel()
+		<-buffered.Done()
+		<-src.Done()
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.IsError(t, elem.err, context.Canceled)
+
+		it.Close()
+
+		_, valid = it.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should close the whole chain when cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		src := makeInfinite()
+		it := WithContext(ctx, Apply(src, func(v int) (int, error) {
+			return v * 2, nil
+		}))
+		assertValues(t, it, []int{0, 2}, false)
+
+		cancel()
+		<-src.Done()
+
+		_, valid := src.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should unblock a waiting call at the deadline", func(t *testing.T) {
+		block := make(chan interface{})
+		defer close(block)
+
+		src := &Iter[int]{
+			next: func() (Elem[int], bool) {
+				<-block
+				return DoneElem[int]()
+			},
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		elem, valid := WithContext(ctx, src).Next()
+		assert.True(t, valid)
+		assert.IsError(t, elem.err, context.DeadlineExceeded)
+	})
+
+	t.Run("should end with the source", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		it := WithContext(ctx, makeFinite(2))
+		assertValues(t, it, []int{0, 1}, true)
+	})
+}
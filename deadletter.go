@@ -0,0 +1,214 @@
+package funky
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// A DeadLetter records an input that a stage of a pipeline failed
+// to process, along with why, so that it can be looked at, or
+// replayed, later.
+type DeadLetter[T any] struct {
+	// Stage names the part of the pipeline that failed.
+	Stage string
+
+	// Seq is the position of the input among those the stage has
+	// seen, counting from zero.
+	Seq uint64
+
+	// Input is the value that the stage failed to process.
+	Input T
+
+	// Err is the reason it failed.
+	Err error
+}
+
+// deadLetterJSON is how a DeadLetter looks as JSON. Errors can't be
+// encoded directly, so only the message is kept.
+type deadLetterJSON[T any] struct {
+	Stage string `json:"stage"`
+	Seq   uint64 `json:"seq"`
+	Input T      `json:"input"`
+	Error string `json:"error,omitempty"`
+}
+
+func (d DeadLetter[T]) MarshalJSON() ([]byte, error) {
+	var msg string
+	if d.Err != nil {
+		msg = d.Err.Error()
+	}
+
+	return json.Marshal(deadLetterJSON[T]{
+		Stage: d.Stage,
+		Seq:   d.Seq,
+		Input: d.Input,
+		Error: msg,
+	})
+}
+
+// UnmarshalJSON reads a DeadLetter written by MarshalJSON. The error
+// comes back with the same message, but it won't match the original
+// with errors.Is or errors.As.
+func (d *DeadLetter[T]) UnmarshalJSON(data []byte) error {
+	var decoded deadLetterJSON[T]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	d.Stage = decoded.Stage
+	d.Seq = decoded.Seq
+	d.Input = decoded.Input
+	d.Err = nil
+	if decoded.Error != "" {
+		d.Err = errors.New(decoded.Error)
+	}
+
+	return nil
+}
+
+// A DeadLetterSink receives dead letters. Put may be called from
+// several goroutines at once, so implementations must handle their
+// own synchronization.
+type DeadLetterSink[T any] interface {
+	Put(letter DeadLetter[T]) error
+}
+
+// SinkFunc adapts a function for use as a DeadLetterSink.
+type SinkFunc[T any] func(DeadLetter[T]) error
+
+func (f SinkFunc[T]) Put(letter DeadLetter[T]) error {
+	return f(letter)
+}
+
+// MemorySink is a DeadLetterSink that keeps dead letters in memory.
+// The zero value is ready to use.
+type MemorySink[T any] struct {
+	letters []DeadLetter[T]
+	lock    sync.Mutex
+}
+
+func (s *MemorySink[T]) Put(letter DeadLetter[T]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.letters = append(s.letters, letter)
+	return nil
+}
+
+// Letters returns a copy of the dead letters received so far, in
+// the order they arrived.
+func (s *MemorySink[T]) Letters() []DeadLetter[T] {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]DeadLetter[T]{}, s.letters...)
+}
+
+// JSONLinesSink is a DeadLetterSink that writes each dead letter to
+// a writer, such as a file, as JSON on its own line. The lines can
+// be read back with FromJSONLines.
+//
+// Example:
+//
+//	letters := FromJSONLines[DeadLetter[Order]](file)
+//	retried := ApplyWithDeadLetter(Apply(letters, Input), "submit", submit, sink)
+type JSONLinesSink[T any] struct {
+	enc  *json.Encoder
+	lock sync.Mutex
+}
+
+// NewJSONLinesSink creates a JSONLinesSink that writes to w. Each
+// dead letter is written as soon as it arrives, so w should be
+// buffered if that matters.
+func NewJSONLinesSink[T any](w io.Writer) *JSONLinesSink[T] {
+	return &JSONLinesSink[T]{
+		enc: json.NewEncoder(w),
+	}
+}
+
+func (s *JSONLinesSink[T]) Put(letter DeadLetter[T]) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.enc.Encode(letter)
+}
+
+// Input is an Applier that extracts the input from a dead letter,
+// which is handy for replaying it.
+func Input[T any](letter DeadLetter[T]) (T, error) {
+	return letter.Input, nil
+}
+
+// ApplyWithDeadLetter is like Apply, but when f fails, the input
+// that caused it goes to the sink, along with the error, the stage
+// name and the input's position, and nothing is produced in its
+// place. Errors that arrive from the input are passed along, as
+// they would be by Apply, since they belong to an earlier stage.
+//
+// If the sink fails, the element is produced after all, carrying
+// both errors, so that nothing is lost.
+//
+// Example: orders := ApplyWithDeadLetter(lines, "parse", parseOrder, NewJSONLinesSink[string](file))
+func ApplyWithDeadLetter[I, O any](it *Iter[I], stage string, f Applier[I, O], sink DeadLetterSink[I]) *Iter[O] {
+	// The lock keeps the sequence numbers in the same order as the
+	// inputs, even when Next is called from several goroutines.
+	var seq uint64
+	var lock sync.Mutex
+
+	pull := func() (Elem[I], uint64, bool) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		inElem, valid := it.Next()
+		seq++
+		return inElem, seq - 1, valid
+	}
+
+	return &Iter[O]{
+		next: func() (Elem[O], bool) {
+			for {
+				inElem, mySeq, valid := pull()
+
+				if !valid {
+					return DoneElem[O]()
+				}
+
+				if inElem.err != nil {
+					return ErrElem[O](fmt.Errorf("apply input error: %w", inElem.err))
+				}
+
+				outVal, err := f(inElem.val)
+				if err == nil {
+					return ValElem(outVal)
+				}
+
+				if err := putDeadLetter(sink, stage, mySeq, inElem.val, err); err != nil {
+					return ErrElem[O](err)
+				}
+			}
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// putDeadLetter sends a dead letter to the sink. If the sink fails,
+// the returned error carries both the original error and the one
+// from the sink, so that the caller can pass it along instead.
+func putDeadLetter[T any](sink DeadLetterSink[T], stage string, seq uint64, input T, err error) error {
+	putErr := sink.Put(DeadLetter[T]{
+		Stage: stage,
+		Seq:   seq,
+		Input: input,
+		Err:   err,
+	})
+	if putErr != nil {
+		return errors.Join(err, fmt.Errorf("dead letter error: %w", putErr))
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package funky
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestDeadLetter(t *testing.T) {
+	t.Run("should round trip through JSON", func(t *testing.T) {
+		var sb strings.Builder
+		sink := NewJSONLinesSink[string](&sb)
+
+		err := sink.Put(DeadLetter[string]{Stage: "parse", Seq: 2, Input: "x", Err: errors.New("bad")})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"stage":"parse","seq":2,"input":"x","error":"bad"}`+"\n", sb.String())
+
+		letters := FromJSONLines[DeadLetter[string]](strings.NewReader(sb.String()))
+		elem, valid := letters.Next()
+		assert.True(t, valid)
+		assert.NoError(t, elem.err)
+		assert.Equal(t, "parse", elem.val.Stage)
+		assert.Equal(t, uint64(2), elem.val.Seq)
+		assert.Equal(t, "x", elem.val.Input)
+		assert.EqualError(t, elem.val.Err, "bad")
+	})
+}
+
+func TestApplyWithDeadLetter(t *testing.T) {
+	t.Run("should send failed inputs to the sink", func(t *testing.T) {
+		sink := &MemorySink[string]{}
+		parsed := ApplyWithDeadLetter(FromVals("1", "x", "3", "y"), "parse", strconv.Atoi, sink)
+
+		assertValues(t, parsed, []int{1, 3}, true)
+
+		letters := sink.Letters()
+		assert.Equal(t, 2, len(letters))
+		assert.Equal(t, "parse", letters[0].Stage)
+		assert.Equal(t, uint64(1), letters[0].Seq)
+		assert.Equal(t, "x", letters[0].Input)
+		assert.IsError(t, letters[0].Err, strconv.ErrSyntax)
+		assert.Equal(t, uint64(3), letters[1].Seq)
+		assert.Equal(t, "y", letters[1].Input)
+	})
+
+	t.Run("should pass through input errors", func(t *testing.T) {
+		sink := &MemorySink[int]{}
+		applied := ApplyWithDeadLetter(makeMixed(), "double", func(x int) (int, error) {
+			return x * 2, nil
+		}, sink)
+
+		assertValues(t, applied, []int{2}, false)
+		elem, valid := applied.Next()
+		assert.True(t, valid)
+		assert.EqualError(t, elem.err, "apply input error: error")
+		assertValues(t, applied, []int{4}, true)
+		assert.Equal(t, 0, len(sink.Letters()))
+	})
+
+	t.Run("should keep errors the sink fails to take", func(t *testing.T) {
+		full := errors.New("full")
+		sink := SinkFunc[string](func(DeadLetter[string]) error {
+			return full
+		})
+		parsed := ApplyWithDeadLetter(FromVals("x"), "parse", strconv.Atoi, sink)
+
+		elem, valid := parsed.Next()
+		assert.True(t, valid)
+		assert.IsError(t, elem.err, strconv.ErrSyntax)
+		assert.IsError(t, elem.err, full)
+		assertValues(t, parsed, []int{}, true)
+	})
+
+	t.Run("should replay dead letters", func(t *testing.T) {
+		var sb strings.Builder
+		sink := NewJSONLinesSink[string](&sb)
+		parsed := ApplyWithDeadLetter(FromVals("1", " 2"), "parse", strconv.Atoi, sink)
+		assertValues(t, parsed, []int{1}, true)
+
+		letters := FromJSONLines[DeadLetter[string]](strings.NewReader(sb.String()))
+		replayed := Apply(Apply(letters, Input), func(s string) (int, error) {
+			return strconv.Atoi(strings.TrimSpace(s))
+		})
+		assertValues(t, replayed, []int{2}, true)
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		source := makeInfinite()
+		ApplyWithDeadLetter(source, "noop", func(x int) (int, error) {
+			return x, nil
+		}, &MemorySink[int]{}).Close()
+		assertClosed(t, source)
+	})
+}
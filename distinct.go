@@ -0,0 +1,111 @@
+package funky
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Distinct skips values that have already been seen. Errors are
+// passed along, untouched.
+//
+// If window is zero, every value is remembered, so memory use grows
+// with the number of distinct values. Otherwise, only the window
+// most recently seen values are remembered, which bounds memory
+// use, but allows a value to reappear once it has gone unseen for
+// long enough to fall out of the window. This is usually fine for
+// unbounded streams, where duplicates tend to arrive close together.
+//
+// For example (in pseudocode):
+//
+//	Distinct({1, 2, 1, 3, 2}, 0) -> {1, 2, 3}
+func Distinct[T comparable](it *Iter[T], window uint64) *Iter[T] {
+	return DistinctBy(it, func(v T) T {
+		return v
+	}, window)
+}
+
+// DistinctBy is like Distinct, but it compares values by the key
+// produced by the given function, so only the first value with any
+// given key is kept.
+//
+// For example (in pseudocode):
+//
+//	DistinctBy({1, 2, 3, 4}, x -> x % 2, 0) -> {1, 2}
+func DistinctBy[T any, K comparable](it *Iter[T], key KeyFunc[T, K], window uint64) *Iter[T] {
+	seen := newKeySet[K](window)
+	var lock sync.Mutex
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			for {
+				elem, valid := it.Next()
+				if !valid {
+					return DoneElem[T]()
+				}
+
+				if elem.err != nil {
+					return elem, true
+				}
+
+				k := key(elem.val)
+
+				lock.Lock()
+				fresh := seen.add(k)
+				lock.Unlock()
+
+				if fresh {
+					return elem, true
+				}
+			}
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// keySet remembers keys, optionally forgetting the least recently
+// seen once there are more than limit of them.
+type keySet[K comparable] struct {
+	limit uint64
+	keys  map[K]*list.Element
+
+	// order holds the keys with the most recently seen at the
+	// front, it is only used when there is a limit.
+	order *list.List
+}
+
+func newKeySet[K comparable](limit uint64) *keySet[K] {
+	return &keySet[K]{
+		limit: limit,
+		keys:  make(map[K]*list.Element),
+		order: list.New(),
+	}
+}
+
+// add records that the key has been seen and reports whether it
+// was new.
+func (s *keySet[K]) add(k K) bool {
+	if e, ok := s.keys[k]; ok {
+		if s.limit > 0 {
+			s.order.MoveToFront(e)
+		}
+
+		return false
+	}
+
+	var e *list.Element
+	if s.limit > 0 {
+		e = s.order.PushFront(k)
+
+		if uint64(s.order.Len()) > s.limit {
+			oldest := s.order.Back()
+			s.order.Remove(oldest)
+			delete(s.keys, oldest.Value.(K))
+		}
+	}
+
+	s.keys[k] = e
+
+	return true
+}
@@ -0,0 +1,63 @@
+package funky
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestDistinct(t *testing.T) {
+	t.Run("should remove every duplicate", func(t *testing.T) {
+		it := Distinct(FromVals(1, 2, 1, 3, 2, 1), 0)
+		assertValues(t, it, []int{1, 2, 3}, true)
+	})
+
+	t.Run("should remember only the window", func(t *testing.T) {
+		it := Distinct(FromVals(1, 2, 1, 3, 4, 1, 4), 2)
+		assertValues(t, it, []int{1, 2, 3, 4, 1}, true)
+	})
+
+	t.Run("should pass through errors", func(t *testing.T) {
+		it := Distinct(makeFrom([]Elem[int]{
+			{val: 1},
+			{err: errRead},
+			{val: 1},
+			{err: errRead},
+		}), 0)
+
+		assertValues(t, it, []int{1}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		elem, valid = it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []int{}, true)
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		src := makeFinite(3)
+		Distinct(src, 0).Close()
+		assertClosed(t, src)
+	})
+}
+
+func TestDistinctBy(t *testing.T) {
+	t.Run("should keep the first value for each key", func(t *testing.T) {
+		it := DistinctBy(FromVals("apple", "avocado", "banana", "blueberry", "cherry"), func(v string) string {
+			return v[:1]
+		}, 0)
+		assertValues(t, it, []string{"apple", "banana", "cherry"}, true)
+	})
+
+	t.Run("should refresh keys that are seen again", func(t *testing.T) {
+		// Seeing "A" again keeps "a" in the window when "c" arrives,
+		// so it is "b" that gets forgotten instead.
+		it := DistinctBy(FromVals("a", "B", "A", "c", "a"), strings.ToLower, 2)
+		assertValues(t, it, []string{"a", "B", "c"}, true)
+	})
+}
@@ -14,5 +14,8 @@ func Each[T any](it *Iter[T], fn func(T, error)) *Iter[T] {
 
 			return elem, valid
 		},
+		close: func() {
+			it.Close()
+		},
 	}
 }
@@ -21,6 +21,14 @@ func TestEach(t *testing.T) {
 		assert.Equal(t, []int{0, 1, 2}, r.values)
 		assert.Equal(t, []error{nil, nil, nil}, r.errors)
 	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		src := makeFinite(3)
+		r := &Recorder[int]{}
+		Each(src, r.Call).Close()
+
+		assertClosed(t, src)
+	})
 }
 
 type Recorder[T any] struct {
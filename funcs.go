@@ -41,42 +41,6 @@ package funky
 // 	}
 // }
 
-// Unchunk reverses the Chunk operation and properly handles the
-// fact that the final chunk may have a different length than
-// the others. In fact, all chunks may be arbitrary lengths.
-//
-// For now, the entire error for a given chunk is passed with
-// each of its values. In the future, this function may attempt
-// to pair wrapped errors with their corresponding value.
-//
-// For example:
-//
-//	Unchunk([]{{1, 2}, {3, 4}}) -> {1, 2, 3, 4}
-// func Unchunk[T any](iterator Iter[[]T]) Iter[T] {
-// 	var next []T
-// 	var errs error
-//
-// 	return func() (T, bool, error) {
-// 		if len(next) == 0 {
-// 			value, more, err := iterator()
-// 			if !more {
-// 				next = nil
-// 				errs = nil
-//
-// 				return Done[T](err)
-// 			}
-//
-// 			next = value
-// 			errs = err
-// 		}
-//
-// 		value := next[0]
-// 		next = next[1:]
-//
-// 		return Result(value, errs)
-// 	}
-// }
-
 // Each applies the given function to each element produced by the
 // iterator.
 //
@@ -0,0 +1,141 @@
+package funky
+
+import "sync"
+
+// GroupBy collects the values from the iterator into groups that
+// share a key, producing each key along with all the values that
+// had that key, in the order they were produced. Groups are
+// produced in the order in which their keys were first seen.
+//
+// Since a group can't be complete until the input has been
+// exhausted, the entire input is consumed, and held in memory,
+// the first time Next is called. Errors can't be assigned to a
+// group, so they are passed along, in order, ahead of the groups.
+// If the key function, or the input, panics along the way, the
+// groups gathered so far are lost, so the iterator ends.
+//
+// For example (in pseudocode):
+//
+//	GroupBy({1, 2, 3, 4}, x -> x % 2) -> {{1, {1, 3}}, {0, {2, 4}}}
+func GroupBy[T any, K comparable](it *Iter[T], key KeyFunc[T, K]) *Iter[Pair[K, []T]] {
+	var groups []Elem[Pair[K, []T]]
+	loaded := false
+	var lock sync.Mutex
+
+	return &Iter[Pair[K, []T]]{
+		next: func() (Elem[Pair[K, []T]], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			// Marked as loaded first so that, if grouping panics,
+			// we end rather than group whatever is left.
+			if !loaded {
+				loaded = true
+				groups = groupAll(it, key)
+			}
+
+			if len(groups) == 0 {
+				return DoneElem[Pair[K, []T]]()
+			}
+
+			elem := groups[0]
+			groups = groups[1:]
+
+			return elem, true
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// groupAll drains the iterator and returns its errors, followed
+// by its groups, as elements ready to be handed out by GroupBy.
+func groupAll[T any, K comparable](it *Iter[T], key KeyFunc[T, K]) []Elem[Pair[K, []T]] {
+	var errs []Elem[Pair[K, []T]]
+	var groups []Elem[Pair[K, []T]]
+	index := make(map[K]int)
+
+	for elem, valid := it.Next(); valid; elem, valid = it.Next() {
+		if elem.err != nil {
+			errs = append(errs, Elem[Pair[K, []T]]{err: elem.err})
+			continue
+		}
+
+		k := key(elem.val)
+		i, seen := index[k]
+		if !seen {
+			i = len(groups)
+			index[k] = i
+			groups = append(groups, Elem[Pair[K, []T]]{
+				val: Pair[K, []T]{Left: k},
+			})
+		}
+
+		groups[i].val.Right = append(groups[i].val.Right, elem.val)
+	}
+
+	return append(errs, groups...)
+}
+
+// GroupBySorted is like GroupBy, but it assumes that the values
+// are already sorted (or at least clustered) by key, so it can
+// produce each group as soon as the key changes rather than
+// waiting for the input to be exhausted. If the same key appears
+// in two separate runs, it will produce two separate groups.
+//
+// Errors are passed along as soon as they are seen, so an error
+// may arrive before the group that was being assembled when it
+// occurred.
+//
+// For example (in pseudocode):
+//
+//	GroupBySorted({1, 1, 2, 1}, x -> x) -> {{1, {1, 1}}, {2, {2}}, {1, {1}}}
+func GroupBySorted[T any, K comparable](it *Iter[T], key KeyFunc[T, K]) *Iter[Pair[K, []T]] {
+	var current Pair[K, []T]
+	started := false
+	var lock sync.Mutex
+
+	return &Iter[Pair[K, []T]]{
+		next: func() (Elem[Pair[K, []T]], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			for {
+				elem, valid := it.Next()
+				if !valid {
+					// Hand over whatever group we were working on,
+					// it won't get any more values now.
+					if started {
+						started = false
+						return ValElem(current)
+					}
+
+					return DoneElem[Pair[K, []T]]()
+				}
+
+				if elem.err != nil {
+					return ErrElem[Pair[K, []T]](elem.err)
+				}
+
+				k := key(elem.val)
+				if started && k == current.Left {
+					current.Right = append(current.Right, elem.val)
+					continue
+				}
+
+				group := current
+				current = Pair[K, []T]{Left: k, Right: []T{elem.val}}
+
+				if started {
+					return ValElem(group)
+				}
+
+				started = true
+			}
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
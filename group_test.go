@@ -0,0 +1,97 @@
+package funky
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestGroupBy(t *testing.T) {
+	t.Run("should group values by key", func(t *testing.T) {
+		it := GroupBy(makeFinite(5), func(v int) int {
+			return v % 2
+		})
+		assertValues(t, it, []Pair[int, []int]{
+			{0, []int{0, 2, 4}},
+			{1, []int{1, 3}},
+		}, true)
+	})
+
+	t.Run("should handle an empty iterator", func(t *testing.T) {
+		it := GroupBy(makeFinite(0), func(v int) int {
+			return v
+		})
+		assertValues(t, it, []Pair[int, []int]{}, true)
+	})
+
+	t.Run("should pass errors ahead of groups", func(t *testing.T) {
+		it := GroupBy(makeFrom([]Elem[int]{
+			{val: 1},
+			{err: errors.New("error")},
+			{val: 2},
+		}), func(v int) bool {
+			return v > 0
+		})
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []Pair[bool, []int]{
+			{true, []int{1, 2}},
+		}, true)
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		src := makeFinite(3)
+		it := GroupBy(src, func(v int) int {
+			return v
+		})
+		it.Close()
+
+		_, valid := src.Next()
+		assert.False(t, valid)
+	})
+}
+
+func TestGroupBySorted(t *testing.T) {
+	t.Run("should group runs of keys", func(t *testing.T) {
+		it := GroupBySorted(FromVals(1, 1, 2, 3, 3, 1), func(v int) int {
+			return v
+		})
+		assertValues(t, it, []Pair[int, []int]{
+			{1, []int{1, 1}},
+			{2, []int{2}},
+			{3, []int{3, 3}},
+			{1, []int{1}},
+		}, true)
+	})
+
+	t.Run("should handle an empty iterator", func(t *testing.T) {
+		it := GroupBySorted(makeFinite(0), func(v int) int {
+			return v
+		})
+		assertValues(t, it, []Pair[int, []int]{}, true)
+	})
+
+	t.Run("should pass errors along immediately", func(t *testing.T) {
+		it := GroupBySorted(makeFrom([]Elem[int]{
+			{val: 1},
+			{err: errors.New("error")},
+			{val: 1},
+			{val: 2},
+		}), func(v int) int {
+			return v
+		})
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []Pair[int, []int]{
+			{1, []int{1, 1}},
+			{2, []int{2}},
+		}, true)
+	})
+}
@@ -3,11 +3,12 @@ package funky
 import (
 	"iter"
 	"sync"
+	"sync/atomic"
 )
 
-// Elem is an internal iterator element that captures an
-// error along with a value. In this way, errors are treated
-// as first class values.
+// Elem is an iterator element that captures an error along
+// with a value. In this way, errors are treated as first class
+// values.
 type Elem[T any] struct {
 	val T
 	err error
@@ -15,6 +16,18 @@ type Elem[T any] struct {
 	ok bool
 }
 
+// Value returns the element's value. If the element carries an
+// error, the value may be the zero value, or it may be a partial
+// result, depending on where the element came from.
+func (e Elem[T]) Value() T {
+	return e.val
+}
+
+// Err returns the element's error, if it has one.
+func (e Elem[T]) Err() error {
+	return e.err
+}
+
 // DoneElem is a helper for returning an invalid iterator
 // response, which is necessary once an iterator has been stopped
 // or exhausted.
@@ -22,16 +35,31 @@ func DoneElem[T any]() (Elem[T], bool) {
 	return Elem[T]{}, false
 }
 
+// ValElem is a helper for returning a valid iterator response
+// that carries a value.
 func ValElem[T any](val T) (Elem[T], bool) {
 	return Elem[T]{val: val}, true
 }
 
+// ErrElem is a helper for returning a valid iterator response
+// that carries an error instead of a value.
 func ErrElem[T any](err error) (Elem[T], bool) {
 	return Elem[T]{err: err}, true
 }
 
-// Iter is an internal iterator that supports all the fun
+// ResultElem is a helper for returning a valid iterator response
+// that carries both a value and an error, such as a partial result.
+func ResultElem[T any](val T, err error) (Elem[T], bool) {
+	return Elem[T]{val: val, err: err}, true
+}
+
+// Iter is an iterator that supports all the fun
 // stuff provided elsewhere.
+//
+// Functions that accept an iterator and return a new one take
+// ownership of the original, so closing the new iterator also
+// closes the original, and so on, all the way up a pipeline.
+// Use Detach to share an iterator without giving up ownership.
 type Iter[T any] struct {
 	// next is a callback that returns the next element in the
 	// iterator. This function may be called concurrently from
@@ -52,14 +80,49 @@ type Iter[T any] struct {
 	// implementation synchronizes reads and writes.
 	close func()
 
-	lock sync.RWMutex
+	// done is closed once the iterator has been closed. It is
+	// created lazily so that iterators can be constructed as
+	// struct literals.
+	done     chan struct{}
+	doneOnce sync.Once
+
+	// closed mirrors next being set to nil, but it can be read
+	// safely without holding the lock.
+	closed    atomic.Bool
+	closeOnce sync.Once
+	lock      sync.RWMutex
+}
+
+// New creates an iterator from callbacks, which allows new sources
+// and operators to be built outside this package. The next callback
+// works like those used throughout this package: it returns the
+// next element, along with true, or an invalid response, such as
+// the one provided by DoneElem, once there is nothing left.
+//
+// The close callback is optional, and is called once when the
+// iterator is closed. Next is never called once Close has returned,
+// but next may be called concurrently from several goroutines, so
+// it must handle its own synchronization, if necessary.
+//
+// Example:
+//
+//	n := 0
+//	counter := New(func() (Elem[int], bool) {
+//		n++
+//		return ValElem(n)
+//	}, nil)
+func New[T any](next func() (Elem[T], bool), close func()) *Iter[T] {
+	return &Iter[T]{
+		next:  next,
+		close: close,
+	}
 }
 
 // Next provides the next value from the iterator.
 func (it *Iter[T]) Next() (elem Elem[T], valid bool) {
 	// Short-circuit here to avoid the lock for a stopped
 	// iterator (since they can't be un-stopped).
-	if it.next == nil {
+	if it.closed.Load() {
 		return DoneElem[T]()
 	}
 
@@ -77,44 +140,131 @@ func (it *Iter[T]) Next() (elem Elem[T], valid bool) {
 	return DoneElem[T]()
 }
 
-// Close tells the iterator to stop producing new values. Some
-// iterators may allow Next to return additional values, such as
-// when values have been buffered.
+// Close tells the iterator to stop producing values. Once Close
+// returns, Next reports that the iterator is exhausted, and any
+// values that were buffered, but not yet read, are discarded. An
+// iterator built from another owns it, so closing it closes the
+// original as well, unless the original was wrapped with Detach.
+// It is safe to call Close more than once, and from multiple
+// goroutines.
 func (it *Iter[T]) Close() {
-	// If we have already closed, we can just bail since
-	// there is no way to un-close.
-	if it.next == nil {
-		return
-	}
+	// There is no way to un-close, so only the first call needs
+	// to do any work. Later calls wait for the first to finish.
+	it.closeOnce.Do(func() {
+		// Call the close handler, if one was provided. This is
+		// just intended for cleanup, so we don't have to worry
+		// much about it. We call close before setting next to nil
+		// to allow it to block if we've got anything in flight.
+		if it.close != nil {
+			it.close()
+		}
 
-	// Call the close handler, if one was provided. This is
-	// just intended for cleanup, so we don't have to worry
-	// much about it. We call close before setting next to nil
-	// to allow it to block if we've got anything in flight.
-	if it.close != nil {
-		it.close()
-		it.close = nil
-	}
+		// Get the write lock because we're going to update the
+		// state, but we want to wait for outstanding reads to finish.
+		it.lock.Lock()
+		defer it.lock.Unlock()
+
+		// A stopped iterator has a nil next() callback, that's
+		// how we define it, so this is all we have to do.
+		it.next = nil
+		it.closed.Store(true)
+
+		it.Done()
+		close(it.done)
+	})
+}
 
-	// Get the write lock because we're going to update the
-	// state, but we want to wait for outstanding reads to finish.
-	it.lock.Lock()
-	defer it.lock.Unlock()
+// Done returns a channel that is closed once the iterator has
+// been closed. This is useful for goroutines that need to give
+// up on an iterator that nobody is reading from anymore.
+func (it *Iter[T]) Done() <-chan struct{} {
+	it.doneOnce.Do(func() {
+		it.done = make(chan struct{})
+	})
 
-	// A stopped iterator has a nil next() callback, that's
-	// how we define it, so this is all we have to do.
-	it.next = nil
+	return it.done
 }
 
-func (it *Iter[T]) ToChan() chan<- T {
-	panic("not implemented")
+// Detach wraps the iterator so that closing the wrapper, or
+// anything built on top of it, leaves the original open. This is
+// useful when an iterator is shared, or will be used again later.
+//
+// Example: firstTen := Take(Detach(lines), 10)
+func Detach[T any](it *Iter[T]) *Iter[T] {
+	return &Iter[T]{
+		next: it.Next,
+	}
+}
+
+// ToChan starts a goroutine that pumps elements from the iterator
+// into the returned channel, which is closed once the iterator is
+// exhausted or closed. Errors are delivered alongside values, so
+// nothing is lost in the hand-off.
+//
+// The channel is unbuffered, so the goroutine only pulls a new
+// element once the previous one has been received. If the reader
+// stops early, it should close the iterator so the goroutine can
+// exit, FromElemChan does this when given the iterator's Close
+// method.
+//
+// There is nowhere to raise a panic from the iterator again, so it
+// arrives as an element carrying a *PanicError, as if it had been
+// caught by Recover.
+func (it *Iter[T]) ToChan() <-chan Elem[T] {
+	c := make(chan Elem[T])
+
+	go func() {
+		defer close(c)
+
+		next := func() (Elem[T], bool) {
+			elem, valid := catchPanic(it.Next)
+			if p, ok := elem.err.(caughtPanic); ok {
+				elem.err = p.PanicError
+			}
+
+			return elem, valid
+		}
+
+		for elem, valid := next(); valid; elem, valid = next() {
+			select {
+			case c <- elem:
+			case <-it.Done():
+				return
+			}
+		}
+	}()
+
+	return c
 }
 
+// ToSeq adapts the iterator for use with range-over-func loops.
+// Errors are discarded, so use ToSeq2 unless the iterator is known
+// not to produce any. If the loop ends early, the iterator is
+// closed.
+//
+// Example: for v := range it.ToSeq() { ... }
 func (it *Iter[T]) ToSeq() iter.Seq[T] {
 	return func(yield func(T) bool) {
 		for elem, valid := it.Next(); valid; elem, valid = it.Next() {
 			more := yield(elem.val)
 			if !more {
+				it.Close()
+				break
+			}
+		}
+	}
+}
+
+// ToSeq2 is like ToSeq, but it provides each value along with its
+// error, so none are lost.
+//
+// Example: for v, err := range it.ToSeq2() { ... }
+func (it *Iter[T]) ToSeq2() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for elem, valid := it.Next(); valid; elem, valid = it.Next() {
+			more := yield(elem.val, elem.err)
+			if !more {
+				it.Close()
 				break
 			}
 		}
@@ -133,8 +283,68 @@ func (it *Iter[T]) ToSlice(n uint64) []T {
 	return elems
 }
 
-func FromChan[T any](c chan<- T) *Iter[T] {
-	panic("not implemented")
+// FromChan creates an iterator that produces the values received
+// from the given channel. The iterator ends when the channel is
+// closed.
+//
+// Closing the iterator does not close the channel, since that is
+// the sender's job. Instead, abandon, if it isn't nil, is called to
+// tell the sender to stop, such as by cancelling its context, and
+// the channel is drained in the background so that a sender blocked
+// on it can notice, finish up, and close it. Draining only helps a
+// sender that does eventually close the channel, one that never
+// does should be given an abandon function.
+//
+// Example: lines := FromChan(readLines(ctx, file), cancel)
+func FromChan[T any](c <-chan T, abandon func()) *Iter[T] {
+	return fromChan(c, abandon, func(v T) Elem[T] {
+		return Elem[T]{val: v}
+	})
+}
+
+// FromElemChan is like FromChan, but it accepts elements, so the
+// sender can pass errors along with its values. This is the
+// counterpart to ToChan, in which case abandon should close the
+// iterator that feeds the channel.
+//
+// Example: it := FromElemChan(source.ToChan(), source.Close)
+func FromElemChan[T any](c <-chan Elem[T], abandon func()) *Iter[T] {
+	return fromChan(c, abandon, func(elem Elem[T]) Elem[T] {
+		return elem
+	})
+}
+
+func fromChan[C, T any](c <-chan C, abandon func(), toElem func(C) Elem[T]) *Iter[T] {
+	// Closed when the iterator is closed so that a call to
+	// next() blocked on the channel can bail out.
+	stop := make(chan struct{})
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			select {
+			case v, more := <-c:
+				if !more {
+					return DoneElem[T]()
+				}
+
+				return toElem(v), true
+			case <-stop:
+				return DoneElem[T]()
+			}
+		},
+		close: func() {
+			close(stop)
+
+			go func() {
+				for range c {
+				}
+			}()
+
+			if abandon != nil {
+				abandon()
+			}
+		},
+	}
 }
 
 func FromSeq[T any](s iter.Seq[T]) *Iter[T] {
@@ -162,6 +372,33 @@ func FromSeq[T any](s iter.Seq[T]) *Iter[T] {
 	}
 }
 
+// FromSeq2 is like FromSeq, but it accepts a sequence of values
+// paired with errors, such as one created by ToSeq2.
+func FromSeq2[T any](s iter.Seq2[T, error]) *Iter[T] {
+	next, stop := iter.Pull2(s)
+	mut := sync.Mutex{}
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			mut.Lock()
+			defer mut.Unlock()
+
+			val, err, valid := next()
+			if !valid {
+				return DoneElem[T]()
+			}
+
+			return Elem[T]{val: val, err: err}, true
+		},
+		close: func() {
+			mut.Lock()
+			defer mut.Unlock()
+
+			stop()
+		},
+	}
+}
+
 func FromSlice[T any](s []T) *Iter[T] {
 	i := 0
 	mut := sync.Mutex{}
@@ -200,3 +437,29 @@ func FromSlice[T any](s []T) *Iter[T] {
 func FromVals[T any](vals ...T) *Iter[T] {
 	return FromSlice(vals)
 }
+
+// fromElems is like FromSlice, but it produces elements, errors
+// and all.
+func fromElems[T any](elems []Elem[T]) *Iter[T] {
+	i := 0
+	mut := sync.Mutex{}
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			mut.Lock()
+			defer mut.Unlock()
+
+			if i >= len(elems) {
+				return DoneElem[T]()
+			}
+
+			i++
+			return elems[i-1], true
+		},
+		close: func() {
+			mut.Lock()
+			i = len(elems)
+			mut.Unlock()
+		},
+	}
+}
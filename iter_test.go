@@ -7,6 +7,43 @@ import (
 	"github.com/alecthomas/assert/v2"
 )
 
+func TestElem(t *testing.T) {
+	t.Run("should expose its value and error", func(t *testing.T) {
+		err := errors.New("error")
+		elem, valid := ResultElem(1, err)
+
+		assert.True(t, valid)
+		assert.Equal(t, 1, elem.Value())
+		assert.Equal(t, err, elem.Err())
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Run("should produce values from the callback", func(t *testing.T) {
+		n := 0
+		it := New(func() (Elem[int], bool) {
+			n++
+			return ValElem(n)
+		}, nil)
+
+		assertValues(t, it, []int{1, 2}, false)
+	})
+
+	t.Run("should call the close callback once", func(t *testing.T) {
+		calls := 0
+		it := New(func() (Elem[int], bool) {
+			return DoneElem[int]()
+		}, func() {
+			calls++
+		})
+
+		it.Close()
+		it.Close()
+
+		assert.Equal(t, 1, calls)
+	})
+}
+
 func TestIter_Next(t *testing.T) {
 	t.Run("should provide a value", func(t *testing.T) {
 		it := &Iter[int]{
@@ -45,6 +82,155 @@ func TestIter_Stop(t *testing.T) {
 	})
 }
 
+func TestIter_Done(t *testing.T) {
+	t.Run("should be closed when the iterator is closed", func(t *testing.T) {
+		it := makeInfinite()
+
+		select {
+		case <-it.Done():
+			t.Fatal("done before close")
+		default:
+		}
+
+		it.Close()
+		it.Close()
+
+		_, open := <-it.Done()
+		assert.False(t, open)
+	})
+}
+
+func TestDetach(t *testing.T) {
+	t.Run("should leave the original open", func(t *testing.T) {
+		src := makeInfinite()
+		it := Take(Detach(src), 2)
+		assertValues(t, it, []int{0, 1}, true)
+
+		it.Close()
+
+		assertValues(t, src, []int{2}, false)
+	})
+}
+
+func TestIter_ToChan(t *testing.T) {
+	t.Run("should deliver values and errors", func(t *testing.T) {
+		it := makeFrom([]Elem[int]{
+			{val: 1},
+			{err: errors.New("error")},
+			{val: 2},
+		})
+
+		var elems []Elem[int]
+		for elem := range it.ToChan() {
+			elems = append(elems, elem)
+		}
+
+		assert.Equal(t, 3, len(elems))
+		assert.Equal(t, 1, elems[0].val)
+		assert.Error(t, elems[1].err)
+		assert.Equal(t, 2, elems[2].val)
+	})
+
+	t.Run("should stop pumping when the iterator is closed", func(t *testing.T) {
+		it := makeInfinite()
+		c := it.ToChan()
+
+		elem := <-c
+		assert.Equal(t, 0, elem.val)
+
+		it.Close()
+
+		// The pump may have already pulled one more value before
+		// it noticed the close, but nothing after that.
+		count := 0
+		for range c {
+			count++
+		}
+		assert.True(t, count <= 1)
+	})
+}
+
+func TestFromChan(t *testing.T) {
+	t.Run("should produce values until the channel closes", func(t *testing.T) {
+		c := make(chan int, 3)
+		c <- 1
+		c <- 2
+		c <- 3
+		close(c)
+
+		it := FromChan(c, nil)
+		assertValues(t, it, []int{1, 2, 3}, true)
+	})
+
+	t.Run("should release the sender when closed", func(t *testing.T) {
+		c := make(chan int)
+		sent := make(chan interface{})
+		go func() {
+			defer close(sent)
+			defer close(c)
+
+			for i := range 5 {
+				c <- i
+			}
+		}()
+
+		it := FromChan(c, nil)
+		assertValues(t, it, []int{0}, false)
+		it.Close()
+
+		<-sent
+
+		_, valid := it.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should stop an abandoned sender", func(t *testing.T) {
+		c := make(chan int)
+		abandoned := make(chan interface{})
+		sent := make(chan interface{})
+		go func() {
+			defer close(sent)
+			defer close(c)
+
+			for i := 0; ; i++ {
+				select {
+				case c <- i:
+				case <-abandoned:
+					return
+				}
+			}
+		}()
+
+		it := FromChan(c, func() {
+			close(abandoned)
+		})
+		assertValues(t, it, []int{0}, false)
+		it.Close()
+
+		<-sent
+
+		_, valid := it.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should round trip with ToChan", func(t *testing.T) {
+		source := makeErroneous()
+		c := source.ToChan()
+		it := FromElemChan(c, source.Close)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		it.Close()
+		assertClosed(t, source)
+
+		// The pump closes the channel once it sees the source close.
+		for range c {
+		}
+	})
+}
+
 func TestIter_ToSeq(t *testing.T) {
 	t.Run("should close when the iterator is exhausted", func(t *testing.T) {
 		it := makeFinite(3)
@@ -69,10 +255,68 @@ func TestIter_ToSeq(t *testing.T) {
 			}
 		}
 
+		_, valid := it.Next()
+		assert.False(t, valid)
+		assertClosed(t, it)
+	})
+}
+
+func TestIter_ToSeq2(t *testing.T) {
+	t.Run("should provide values and errors", func(t *testing.T) {
+		var values []int
+		var errs []error
+		for v, err := range makeMixed().ToSeq2() {
+			values = append(values, v)
+			errs = append(errs, err)
+		}
+
+		assert.Equal(t, []int{1, 0, 2}, values)
+		assert.NoError(t, errs[0])
+		assert.Error(t, errs[1])
+		assert.NoError(t, errs[2])
+	})
+
+	t.Run("should close when the loop breaks", func(t *testing.T) {
+		it := makeInfinite()
+		for v := range it.ToSeq2() {
+			if v > 0 {
+				break
+			}
+		}
+
+		assertClosed(t, it)
+	})
+}
+
+func TestFromSeq(t *testing.T) {
+	t.Run("should stop the sequence when closed downstream", func(t *testing.T) {
+		stopped := false
+		seq := func(yield func(int) bool) {
+			defer func() { stopped = true }()
+
+			for i := 0; yield(i); i++ {
+			}
+		}
+
+		it := Take(FromSeq(seq), 5)
+		assertValues(t, it, []int{0, 1}, false)
+		it.Close()
+
+		assert.True(t, stopped)
+	})
+}
+
+func TestFromSeq2(t *testing.T) {
+	t.Run("should round trip with ToSeq2", func(t *testing.T) {
+		it := FromSeq2(makeMixed().ToSeq2())
+
+		assertValues(t, it, []int{1}, false)
+
 		elem, valid := it.Next()
 		assert.True(t, valid)
-		assert.NoError(t, elem.err)
-		assert.Equal(t, 2, elem.val)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []int{2}, true)
 	})
 }
 
@@ -146,6 +390,15 @@ func makeErroneous() *Iter[int] {
 	}
 }
 
+// makeMixed produces 1, an error, then 2.
+func makeMixed() *Iter[int] {
+	return makeFrom([]Elem[int]{
+		{val: 1},
+		{err: errors.New("error")},
+		{val: 2},
+	})
+}
+
 func makeConstant[T any](value T) *Iter[T] {
 	return &Iter[T]{
 		next: func() (Elem[T], bool) {
@@ -169,3 +422,13 @@ func assertValues[T any](t *testing.T, it *Iter[T], vals []T, exact bool) {
 		assert.False(t, valid)
 	}
 }
+
+func assertClosed[T any](t *testing.T, it *Iter[T]) {
+	t.Helper()
+
+	select {
+	case <-it.Done():
+	default:
+		t.Fatal("iterator was not closed")
+	}
+}
@@ -0,0 +1,255 @@
+package funky
+
+import (
+	"cmp"
+	"sync"
+)
+
+// HashJoin pairs up values from the two iterators that share a key,
+// like an inner join in SQL. Values that don't match anything on
+// the other side are dropped.
+//
+// The right iterator is consumed, and held in memory, the first
+// time Next is called, so it should be the smaller of the two. The
+// left iterator is then streamed, and its matches are produced in
+// the order of the left values, then of the right values. Errors
+// from the right iterator come first, errors from the left are
+// passed along as they are encountered. If the right key function,
+// or the right iterator, panics while the values are being held,
+// the join can't be completed, so it ends.
+//
+// For example (in pseudocode):
+//
+//	HashJoin({1, 2}, {"a", "bb", "cc"}, x -> x, y -> len(y))
+//	  -> {{1, "a"}, {2, "bb"}, {2, "cc"}}
+func HashJoin[L, R any, K comparable](left *Iter[L], right *Iter[R], leftKey KeyFunc[L, K], rightKey KeyFunc[R, K]) *Iter[Pair[L, R]] {
+	return hashJoin(left, right, leftKey, rightKey, false, false, func(l Option[L], r Option[R]) Pair[L, R] {
+		return Pair[L, R]{l.Value, r.Value}
+	})
+}
+
+// LeftJoin is like HashJoin, but left values that don't match any
+// right values are kept, paired with a missing right value.
+func LeftJoin[L, R any, K comparable](left *Iter[L], right *Iter[R], leftKey KeyFunc[L, K], rightKey KeyFunc[R, K]) *Iter[Pair[L, Option[R]]] {
+	return hashJoin(left, right, leftKey, rightKey, true, false, func(l Option[L], r Option[R]) Pair[L, Option[R]] {
+		return Pair[L, Option[R]]{l.Value, r}
+	})
+}
+
+// FullOuterJoin is like HashJoin, but values from either side that
+// don't match anything on the other side are kept, paired with a
+// missing value. Unmatched right values come last, in their
+// original order, since they can't be identified until the left
+// iterator has been exhausted.
+func FullOuterJoin[L, R any, K comparable](left *Iter[L], right *Iter[R], leftKey KeyFunc[L, K], rightKey KeyFunc[R, K]) *Iter[Pair[Option[L], Option[R]]] {
+	return hashJoin(left, right, leftKey, rightKey, true, true, func(l Option[L], r Option[R]) Pair[Option[L], Option[R]] {
+		return Pair[Option[L], Option[R]]{l, r}
+	})
+}
+
+// hashJoin implements the hash joins, keepLeft and keepRight decide
+// whether unmatched values from each side are kept, and pair builds
+// the output values.
+func hashJoin[L, R any, K comparable, O any](
+	left *Iter[L],
+	right *Iter[R],
+	leftKey KeyFunc[L, K],
+	rightKey KeyFunc[R, K],
+	keepLeft, keepRight bool,
+	pair func(Option[L], Option[R]) O,
+) *Iter[O] {
+	var rights []R
+	var matched []bool
+	index := make(map[K][]int)
+	built := false
+	leftDone := false
+
+	// Elements that are ready to go, a single left value may
+	// match several right values.
+	var queue []Elem[O]
+	var lock sync.Mutex
+
+	return &Iter[O]{
+		next: func() (Elem[O], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			// Marked as built, with nothing left to join, first so
+			// that, if building panics, we end rather than join
+			// against an incomplete index.
+			if !built {
+				built, leftDone = true, true
+
+				for elem, valid := right.Next(); valid; elem, valid = right.Next() {
+					if elem.err != nil {
+						queue = append(queue, Elem[O]{err: elem.err})
+						continue
+					}
+
+					k := rightKey(elem.val)
+					index[k] = append(index[k], len(rights))
+					rights = append(rights, elem.val)
+				}
+
+				matched = make([]bool, len(rights))
+				leftDone = false
+			}
+
+			for len(queue) == 0 {
+				if leftDone {
+					return DoneElem[O]()
+				}
+
+				elem, valid := left.Next()
+				if !valid {
+					leftDone = true
+
+					if keepRight {
+						for i, r := range rights {
+							if !matched[i] {
+								queue = append(queue, Elem[O]{val: pair(Option[L]{}, Option[R]{r, true})})
+							}
+						}
+					}
+
+					continue
+				}
+
+				if elem.err != nil {
+					queue = append(queue, Elem[O]{err: elem.err})
+					continue
+				}
+
+				l := Option[L]{elem.val, true}
+				matches := index[leftKey(elem.val)]
+				if len(matches) == 0 && keepLeft {
+					queue = append(queue, Elem[O]{val: pair(l, Option[R]{})})
+				}
+
+				for _, i := range matches {
+					matched[i] = true
+					queue = append(queue, Elem[O]{val: pair(l, Option[R]{rights[i], true})})
+				}
+			}
+
+			elem := queue[0]
+			queue = queue[1:]
+
+			return elem, true
+		},
+		close: func() {
+			left.Close()
+			right.Close()
+		},
+	}
+}
+
+// MergeJoin is like HashJoin, but it assumes that both iterators
+// produce values sorted, in ascending order, by key. This allows it
+// to stream both sides, holding only the right values that share
+// the current key in memory. Errors from either side are passed
+// along as they are encountered. Once the left iterator runs out,
+// the rest of the right iterator is drained for its errors.
+//
+// If either iterator isn't sorted, some matches will be missed.
+func MergeJoin[L, R any, K cmp.Ordered](left *Iter[L], right *Iter[R], leftKey KeyFunc[L, K], rightKey KeyFunc[R, K]) *Iter[Pair[L, R]] {
+	// The run of right values that share the most recent key.
+	var group []R
+	var groupKey K
+
+	// The first right value past the current group, if we've
+	// already pulled it.
+	var peek R
+	peeked := false
+	leftDone := false
+
+	var queue []Elem[Pair[L, R]]
+	var lock sync.Mutex
+
+	// fill makes sure that the group holds the right values that
+	// have the given key, if there are any.
+	fill := func(k K) {
+		if len(group) > 0 && groupKey == k {
+			return
+		}
+
+		group = nil
+		groupKey = k
+
+		for {
+			if !peeked {
+				elem, valid := right.Next()
+				if !valid {
+					return
+				}
+
+				if elem.err != nil {
+					queue = append(queue, Elem[Pair[L, R]]{err: elem.err})
+					continue
+				}
+
+				peek = elem.val
+				peeked = true
+			}
+
+			c := cmp.Compare(rightKey(peek), k)
+			if c > 0 {
+				return
+			}
+
+			if c == 0 {
+				group = append(group, peek)
+			}
+
+			peeked = false
+		}
+	}
+
+	return &Iter[Pair[L, R]]{
+		next: func() (Elem[Pair[L, R]], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			for len(queue) == 0 {
+				if leftDone {
+					return DoneElem[Pair[L, R]]()
+				}
+
+				elem, valid := left.Next()
+				if !valid {
+					leftDone = true
+
+					// Nothing else on the right can be matched, but
+					// its errors still need to be passed along.
+					for elem, valid := right.Next(); valid; elem, valid = right.Next() {
+						if elem.err != nil {
+							queue = append(queue, Elem[Pair[L, R]]{err: elem.err})
+						}
+					}
+
+					continue
+				}
+
+				if elem.err != nil {
+					queue = append(queue, Elem[Pair[L, R]]{err: elem.err})
+					continue
+				}
+
+				fill(leftKey(elem.val))
+
+				for _, r := range group {
+					queue = append(queue, Elem[Pair[L, R]]{val: Pair[L, R]{elem.val, r}})
+				}
+			}
+
+			elem := queue[0]
+			queue = queue[1:]
+
+			return elem, true
+		},
+		close: func() {
+			left.Close()
+			right.Close()
+		},
+	}
+}
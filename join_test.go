@@ -0,0 +1,123 @@
+package funky
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func byLength(s string) int {
+	return len(s)
+}
+
+func identity(v int) int {
+	return v
+}
+
+func TestHashJoin(t *testing.T) {
+	t.Run("should pair up matching values", func(t *testing.T) {
+		it := HashJoin(FromVals(1, 2, 4), FromVals("a", "bb", "cc", "ddd"), identity, byLength)
+		assertValues(t, it, []Pair[int, string]{
+			{1, "a"},
+			{2, "bb"},
+			{2, "cc"},
+		}, true)
+	})
+
+	t.Run("should pass along errors", func(t *testing.T) {
+		it := HashJoin(makeMixed(), FromVals("a", "bb"), identity, byLength)
+		assertValues(t, it, []Pair[int, string]{{1, "a"}}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []Pair[int, string]{{2, "bb"}}, true)
+	})
+
+	t.Run("should close both sources", func(t *testing.T) {
+		left := makeFinite(2)
+		right := FromVals("a")
+		HashJoin(left, right, identity, byLength).Close()
+
+		assertClosed(t, left)
+		assertClosed(t, right)
+	})
+}
+
+func TestLeftJoin(t *testing.T) {
+	t.Run("should keep unmatched left values", func(t *testing.T) {
+		it := LeftJoin(FromVals(1, 3), FromVals("a", "bb"), identity, byLength)
+		assertValues(t, it, []Pair[int, Option[string]]{
+			{1, Option[string]{"a", true}},
+			{3, Option[string]{}},
+		}, true)
+	})
+}
+
+func TestFullOuterJoin(t *testing.T) {
+	t.Run("should keep unmatched values from both sides", func(t *testing.T) {
+		it := FullOuterJoin(FromVals(1, 3), FromVals("a", "bb", "c"), identity, byLength)
+		assertValues(t, it, []Pair[Option[int], Option[string]]{
+			{Option[int]{1, true}, Option[string]{"a", true}},
+			{Option[int]{1, true}, Option[string]{"c", true}},
+			{Option[int]{3, true}, Option[string]{}},
+			{Option[int]{}, Option[string]{"bb", true}},
+		}, true)
+	})
+}
+
+func TestMergeJoin(t *testing.T) {
+	t.Run("should pair up matching values", func(t *testing.T) {
+		it := MergeJoin(FromVals(1, 2, 2, 4, 5), FromVals("a", "bb", "cc", "ddd", "eeee"), identity, byLength)
+		assertValues(t, it, []Pair[int, string]{
+			{1, "a"},
+			{2, "bb"},
+			{2, "cc"},
+			{2, "bb"},
+			{2, "cc"},
+			{4, "eeee"},
+		}, true)
+	})
+
+	t.Run("should handle an empty side", func(t *testing.T) {
+		it := MergeJoin(FromVals(1, 2), FromVals[string](), identity, byLength)
+		assertValues(t, it, []Pair[int, string]{}, true)
+	})
+
+	t.Run("should pass along errors", func(t *testing.T) {
+		it := MergeJoin(makeMixed(), FromVals("a", "bb"), identity, byLength)
+		assertValues(t, it, []Pair[int, string]{{1, "a"}}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []Pair[int, string]{{2, "bb"}}, true)
+	})
+
+	t.Run("should pass along right errors after the last left value", func(t *testing.T) {
+		it := MergeJoin(FromVals(1), fromElems([]Elem[string]{
+			{val: "a"},
+			{val: "bb"},
+			{err: errors.New("error")},
+		}), identity, byLength)
+		assertValues(t, it, []Pair[int, string]{{1, "a"}}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.EqualError(t, elem.err, "error")
+
+		assertValues(t, it, []Pair[int, string]{}, true)
+	})
+
+	t.Run("should close both sources", func(t *testing.T) {
+		left := makeFinite(2)
+		right := FromVals("a")
+		MergeJoin(left, right, identity, byLength).Close()
+
+		assertClosed(t, left)
+		assertClosed(t, right)
+	})
+}
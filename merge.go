@@ -0,0 +1,230 @@
+package funky
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// Merge creates a single iterator that produces the values from
+// each of the provided iterators, in whatever order they become
+// ready, so a slow iterator doesn't hold up the others. Each input
+// is read on its own goroutine, which starts on the first call to
+// Next. Closing the merged iterator closes every input.
+//
+// Use Concat when the values need to come out in order.
+//
+// Example: events := Merge(clicks, scrolls, keypresses)
+func Merge[T any](its ...*Iter[T]) *Iter[T] {
+	// Every goroutine sends to this channel, it is closed once all
+	// of them have finished.
+	elements := make(chan Elem[T])
+
+	// Closed when the iterator is closed so that the goroutines
+	// below, and any callers waiting on them, can give up.
+	stop := make(chan interface{})
+
+	var startOnce sync.Once
+	start := func() {
+		var pumpGroup sync.WaitGroup
+
+		for _, it := range its {
+			pumpGroup.Add(1)
+
+			go func() {
+				defer pumpGroup.Done()
+
+				next := func() (Elem[T], bool) {
+					return catchPanic(it.Next)
+				}
+
+				for elem, valid := next(); valid; elem, valid = next() {
+					select {
+					case elements <- elem:
+					case <-stop:
+						return
+					}
+				}
+			}()
+		}
+
+		go func() {
+			pumpGroup.Wait()
+			close(elements)
+		}()
+	}
+
+	var stopOnce sync.Once
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			startOnce.Do(start)
+
+			select {
+			case elem, more := <-elements:
+				if !more {
+					return DoneElem[T]()
+				}
+
+				return rethrow(elem, true)
+			case <-stop:
+				return DoneElem[T]()
+			}
+		},
+		close: func() {
+			stopOnce.Do(func() {
+				close(stop)
+			})
+
+			// Closing the inputs frees up any goroutines that are
+			// waiting on a value.
+			for _, it := range its {
+				it.Close()
+			}
+		},
+	}
+}
+
+// Interleave creates a single iterator that takes one value from
+// each of the provided iterators in turn. Iterators that run out
+// are skipped, so the others carry on until they run out as well.
+// Closing the interleaved iterator closes every input.
+//
+// For example (in pseudocode):
+//
+//	Interleave({1, 2, 3}, {4}, {5, 6}) -> {1, 4, 5, 2, 6, 3}
+func Interleave[T any](its ...*Iter[T]) *Iter[T] {
+	// The inputs that haven't run out yet, in turn order.
+	live := append([]*Iter[T]{}, its...)
+	index := 0
+	mut := sync.Mutex{}
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			mut.Lock()
+			defer mut.Unlock()
+
+			for len(live) > 0 {
+				index %= len(live)
+
+				elem, valid := live[index].Next()
+				if !valid {
+					live = append(live[:index], live[index+1:]...)
+					continue
+				}
+
+				index++
+				return elem, true
+			}
+
+			return DoneElem[T]()
+		},
+		close: func() {
+			for _, it := range its {
+				it.Close()
+			}
+		},
+	}
+}
+
+// MergeSorted merges iterators that each produce values in sorted
+// order, according to compare, into a single sorted iterator. When
+// values are equal, those from earlier iterators come first. Errors
+// are passed along as soon as they are encountered. Closing the
+// merged iterator closes every input.
+//
+// For example (in pseudocode):
+//
+//	MergeSorted(cmp.Compare, {1, 4}, {2, 3}) -> {1, 2, 3, 4}
+func MergeSorted[T any](compare func(a, b T) int, its ...*Iter[T]) *Iter[T] {
+	heads := &mergeHeap[T]{compare: compare}
+	var errs []Elem[T]
+	primed := false
+	var lock sync.Mutex
+
+	// pull fetches the next value from the i-th iterator and adds
+	// it to the heap, setting aside any errors it comes across.
+	pull := func(i int) {
+		for elem, valid := its[i].Next(); valid; elem, valid = its[i].Next() {
+			if elem.err != nil {
+				errs = append(errs, elem)
+				continue
+			}
+
+			heap.Push(heads, mergeHead[T]{val: elem.val, src: i})
+			return
+		}
+	}
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if !primed {
+				for i := range its {
+					pull(i)
+				}
+				primed = true
+			}
+
+			if len(errs) > 0 {
+				elem := errs[0]
+				errs = errs[1:]
+				return elem, true
+			}
+
+			if heads.Len() == 0 {
+				return DoneElem[T]()
+			}
+
+			head := heap.Pop(heads).(mergeHead[T])
+			pull(head.src)
+
+			return ValElem(head.val)
+		},
+		close: func() {
+			for _, it := range its {
+				it.Close()
+			}
+		},
+	}
+}
+
+type mergeHead[T any] struct {
+	val T
+	src int
+}
+
+// mergeHeap is a min-heap of values waiting to be merged, ties are
+// broken by the index of the iterator they came from.
+type mergeHeap[T any] struct {
+	heads   []mergeHead[T]
+	compare func(a, b T) int
+}
+
+func (h *mergeHeap[T]) Len() int {
+	return len(h.heads)
+}
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	c := h.compare(h.heads[i].val, h.heads[j].val)
+	if c == 0 {
+		return h.heads[i].src < h.heads[j].src
+	}
+
+	return c < 0
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) {
+	h.heads[i], h.heads[j] = h.heads[j], h.heads[i]
+}
+
+func (h *mergeHeap[T]) Push(x any) {
+	h.heads = append(h.heads, x.(mergeHead[T]))
+}
+
+func (h *mergeHeap[T]) Pop() any {
+	last := h.heads[len(h.heads)-1]
+	h.heads = h.heads[:len(h.heads)-1]
+	return last
+}
@@ -0,0 +1,153 @@
+package funky
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestMerge(t *testing.T) {
+	t.Run("should produce nothing when no iterators given", func(t *testing.T) {
+		merged := Merge[int]()
+		assertValues(t, merged, []int{}, true)
+	})
+
+	t.Run("should produce every value", func(t *testing.T) {
+		merged := Merge(FromVals(1, 2, 3), FromVals(4, 5), FromVals[int]())
+
+		vals := merged.ToSlice(10)
+		slices.Sort(vals)
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, vals)
+	})
+
+	t.Run("should keep the order of each input", func(t *testing.T) {
+		merged := Merge(FromVals(1, 2, 3), FromVals(4, 5, 6))
+
+		var first, second []int
+		for _, v := range merged.ToSlice(10) {
+			if v <= 3 {
+				first = append(first, v)
+			} else {
+				second = append(second, v)
+			}
+		}
+
+		assert.Equal(t, []int{1, 2, 3}, first)
+		assert.Equal(t, []int{4, 5, 6}, second)
+	})
+
+	t.Run("should not wait on a slow input", func(t *testing.T) {
+		slow := make(chan int)
+		merged := Merge(FromChan(slow, nil), FromVals(1, 2))
+
+		assertValues(t, merged, []int{1, 2}, false)
+
+		go func() {
+			slow <- 3
+			close(slow)
+		}()
+
+		assertValues(t, merged, []int{3}, true)
+	})
+
+	t.Run("should pass through errors", func(t *testing.T) {
+		merged := Merge(makeErroneous())
+		elem, valid := merged.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+		merged.Close()
+	})
+
+	t.Run("should close every input", func(t *testing.T) {
+		first := makeInfinite()
+		second := FromChan(make(chan int), nil)
+
+		merged := Merge(first, second)
+		_, valid := merged.Next()
+		assert.True(t, valid)
+		merged.Close()
+
+		assertClosed(t, first)
+		assertClosed(t, second)
+
+		_, valid = merged.Next()
+		assert.False(t, valid)
+	})
+}
+
+func TestInterleave(t *testing.T) {
+	t.Run("should produce nothing when no iterators given", func(t *testing.T) {
+		interleaved := Interleave[int]()
+		assertValues(t, interleaved, []int{}, true)
+	})
+
+	t.Run("should take turns", func(t *testing.T) {
+		interleaved := Interleave(FromVals(1, 2, 3), FromVals(4), FromVals(5, 6))
+		assertValues(t, interleaved, []int{1, 4, 5, 2, 6, 3}, true)
+	})
+
+	t.Run("should pass through errors", func(t *testing.T) {
+		interleaved := Interleave(makeMixed(), FromVals(3))
+
+		elem, _ := interleaved.Next()
+		assert.Equal(t, 1, elem.val)
+		elem, _ = interleaved.Next()
+		assert.Equal(t, 3, elem.val)
+		elem, _ = interleaved.Next()
+		assert.Error(t, elem.err)
+		assertValues(t, interleaved, []int{2}, true)
+	})
+
+	t.Run("should close every input", func(t *testing.T) {
+		first := FromVals(1, 2)
+		second := FromVals(3, 4)
+
+		interleaved := Interleave(first, second)
+		assertValues(t, interleaved, []int{1}, false)
+		interleaved.Close()
+
+		assertClosed(t, first)
+		assertClosed(t, second)
+	})
+}
+
+func TestMergeSorted(t *testing.T) {
+	t.Run("should merge sorted inputs", func(t *testing.T) {
+		merged := MergeSorted(cmp.Compare[int], FromVals(1, 4, 7), FromVals(2, 5), FromVals(3, 6, 8))
+		assertValues(t, merged, []int{1, 2, 3, 4, 5, 6, 7, 8}, true)
+	})
+
+	t.Run("should favor earlier inputs on ties", func(t *testing.T) {
+		merged := MergeSorted(func(a, b Pair[int, string]) int {
+			return cmp.Compare(a.Left, b.Left)
+		},
+			FromVals(Pair[int, string]{1, "a"}, Pair[int, string]{2, "a"}),
+			FromVals(Pair[int, string]{1, "b"}),
+		)
+
+		assertValues(t, merged, []Pair[int, string]{{1, "a"}, {1, "b"}, {2, "a"}}, true)
+	})
+
+	t.Run("should pass through errors", func(t *testing.T) {
+		merged := MergeSorted(cmp.Compare[int], makeMixed())
+
+		assertValues(t, merged, []int{1}, false)
+		elem, _ := merged.Next()
+		assert.Error(t, elem.err)
+		assertValues(t, merged, []int{2}, true)
+	})
+
+	t.Run("should close every input", func(t *testing.T) {
+		first := FromVals(1, 2)
+		second := FromVals(3, 4)
+
+		merged := MergeSorted(cmp.Compare[int], first, second)
+		assertValues(t, merged, []int{1}, false)
+		merged.Close()
+
+		assertClosed(t, first)
+		assertClosed(t, second)
+	})
+}
@@ -0,0 +1,105 @@
+package funky
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error produced by Recover in place of a panic.
+// It holds the value the panic was called with and the stack trace
+// of the goroutine that panicked, which, for operators that run on
+// their own goroutines, such as Buffer, isn't the one that called
+// Next.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value, if it was an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Recover turns panics that happen while producing the next value,
+// such as one in an Applier or a Predicate, into elements carrying a
+// *PanicError, so that one bad value can't take down a long-running
+// pipeline. Since most operators do their work when Next is called,
+// a single Recover at the end of a pipeline protects every stage
+// before it.
+//
+// Operators that do their work on other goroutines, such as Buffer,
+// Parallel, ParallelApply and Merge, catch panics there and raise
+// them again from Next, so Recover catches those too, and the stack
+// trace points at the original panic.
+//
+// The value that caused the panic is lost, but most stages before it
+// are left in working order, so the pipeline carries on with the
+// next value. The exceptions are operators that gather up their
+// input before producing anything, such as GroupBy, SortBy and the
+// hash joins, which lose what they had gathered if it is cut short
+// by a panic, so they end instead.
+//
+// Example: results := Recover(Apply(records, riskyTransform))
+func Recover[T any](it *Iter[T]) *Iter[T] {
+	return &Iter[T]{
+		next: func() (elem Elem[T], valid bool) {
+			defer func() {
+				if p := recover(); p != nil {
+					elem, valid = ErrElem[T](newPanicError(p))
+				}
+			}()
+
+			return it.Next()
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// newPanicError wraps a recovered panic value, a panic that was
+// already wrapped, and raised again by rethrow, is left alone so
+// that it keeps its original stack trace.
+func newPanicError(p any) *PanicError {
+	if pe, ok := p.(*PanicError); ok {
+		return pe
+	}
+
+	return &PanicError{Value: p, Stack: debug.Stack()}
+}
+
+// caughtPanic carries a panic from a background goroutine, inside an
+// element, to whichever goroutine receives the element, where
+// rethrow raises it again. It should never escape from an operator.
+type caughtPanic struct {
+	*PanicError
+}
+
+// catchPanic calls next, which is usually the Next method of an
+// iterator, turning a panic into an element that rethrow will raise
+// again. It is meant for use on background goroutines, where a
+// panic would otherwise crash the program.
+func catchPanic[T any](next func() (Elem[T], bool)) (elem Elem[T], valid bool) {
+	defer func() {
+		if p := recover(); p != nil {
+			elem, valid = Elem[T]{err: caughtPanic{newPanicError(p)}}, true
+		}
+	}()
+
+	return next()
+}
+
+// rethrow raises the panic carried by an element from catchPanic,
+// and passes any other element along.
+func rethrow[T any](elem Elem[T], valid bool) (Elem[T], bool) {
+	if p, ok := elem.err.(caughtPanic); ok {
+		panic(p.PanicError)
+	}
+
+	return elem, valid
+}
@@ -0,0 +1,226 @@
+package funky
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+var errBoom = errors.New("boom")
+
+// panicky doubles its input, but panics on 2.
+func panicky(x int) (int, error) {
+	if x == 2 {
+		panic(errBoom)
+	}
+
+	return x * 2, nil
+}
+
+// assertPanicked checks that the next element carries a panic from
+// panicky.
+func assertPanicked[T any](t *testing.T, it *Iter[T]) {
+	t.Helper()
+
+	elem, valid := it.Next()
+	assert.True(t, valid)
+
+	var pe *PanicError
+	assert.True(t, errors.As(elem.err, &pe))
+	assert.IsError(t, elem.err, errBoom)
+	assert.True(t, strings.Contains(string(pe.Stack), "panicky"))
+}
+
+func TestRecover(t *testing.T) {
+	t.Run("should turn panics into errors", func(t *testing.T) {
+		recovered := Recover(Apply(FromVals(1, 2, 3), panicky))
+
+		assertValues(t, recovered, []int{2}, false)
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{6}, true)
+	})
+
+	t.Run("should wrap panic values that are not errors", func(t *testing.T) {
+		recovered := Recover(Apply(FromVals(1), func(x int) (int, error) {
+			panic("oops")
+		}))
+
+		elem, valid := recovered.Next()
+		assert.True(t, valid)
+		assert.EqualError(t, elem.err, "panic: oops")
+		assert.Equal(t, "oops", elem.err.(*PanicError).Value)
+	})
+
+	t.Run("should recover panics from predicates", func(t *testing.T) {
+		recovered := Recover(Where(FromVals(1, 2, 3), func(x int) bool {
+			_, err := panicky(x)
+			return err == nil
+		}))
+
+		assertValues(t, recovered, []int{1}, false)
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{3}, true)
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		source := makeInfinite()
+		Recover(source).Close()
+		assertClosed(t, source)
+	})
+}
+
+func TestRecoverBackground(t *testing.T) {
+	t.Run("should recover panics from Buffer", func(t *testing.T) {
+		recovered := Recover(Buffer(Apply(FromVals(1, 2, 3), panicky), 2))
+
+		assertValues(t, recovered, []int{2}, false)
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{6}, true)
+	})
+
+	t.Run("should recover panics from Parallel", func(t *testing.T) {
+		recovered := Recover(Parallel(Apply(FromVals(1, 2, 3), panicky), 2))
+
+		assertValues(t, recovered, []int{2}, false)
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{6}, true)
+	})
+
+	t.Run("should recover panics from ParallelApply", func(t *testing.T) {
+		recovered := Recover(ParallelApply(FromVals(1, 2, 3), panicky, 2, ParallelApplyOptions{}))
+
+		assertValues(t, recovered, []int{2}, false)
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{6}, true)
+	})
+
+	t.Run("should recover panics from the source of ParallelApply", func(t *testing.T) {
+		source := Apply(FromVals(1, 2, 3), panicky)
+		recovered := Recover(ParallelApply(source, func(x int) (int, error) {
+			return x + 1, nil
+		}, 2, ParallelApplyOptions{}))
+
+		assertValues(t, recovered, []int{3}, false)
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{7}, true)
+	})
+
+	t.Run("should recover panics from Merge", func(t *testing.T) {
+		recovered := Recover(Merge(Apply(FromVals(2), panicky)))
+
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{}, true)
+	})
+
+	t.Run("should recover panics from WithContext", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		recovered := Recover(WithContext(ctx, Apply(FromVals(1, 2), panicky)))
+
+		assertValues(t, recovered, []int{2}, false)
+		assertPanicked(t, recovered)
+	})
+
+	t.Run("should deliver panics from ToChan as errors", func(t *testing.T) {
+		source := Apply(FromVals(1, 2, 3), panicky)
+		delivered := FromElemChan(source.ToChan(), source.Close)
+
+		assertValues(t, delivered, []int{2}, false)
+		assertPanicked(t, delivered)
+		assertValues(t, delivered, []int{6}, true)
+	})
+
+	t.Run("should raise panics from ParallelReduce on the caller", func(t *testing.T) {
+		defer func() {
+			p := recover()
+			pe, ok := p.(*PanicError)
+			assert.True(t, ok)
+			assert.IsError(t, pe, errBoom)
+		}()
+
+		_, _ = ParallelReduce(fromElems([]Elem[int]{{val: 1}, {val: 2}}), 2, func(sum, x int) (int, error) {
+			_, err := panicky(x)
+			return sum + x, err
+		}, add, ReduceOptions{})
+
+		t.Fatal("expected a panic")
+	})
+}
+
+func TestRecoverLocks(t *testing.T) {
+	t.Run("should leave Tee working after a panic", func(t *testing.T) {
+		outputs := Tee(Apply(FromVals(1, 2, 3), panicky), 2)
+		first := Recover(outputs[0])
+
+		assertValues(t, first, []int{2}, false)
+		assertPanicked(t, first)
+		assertValues(t, first, []int{6}, true)
+		assertValues(t, outputs[1], []int{2, 6}, true)
+	})
+
+	t.Run("should leave Partition working after a panic", func(t *testing.T) {
+		matching, rest := Partition(FromVals(1, 2, 3), func(x int) bool {
+			_, err := panicky(x)
+			return err == nil
+		})
+		recovered := Recover(matching)
+
+		assertValues(t, recovered, []int{1}, false)
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{3}, true)
+		assertValues(t, rest, []int{}, true)
+	})
+
+	t.Run("should leave ApplyWithDeadLetter working after a panic", func(t *testing.T) {
+		recovered := Recover(ApplyWithDeadLetter(Apply(FromVals(1, 2, 3), panicky), "double", func(x int) (int, error) {
+			return x, nil
+		}, &MemorySink[int]{}))
+
+		assertValues(t, recovered, []int{2}, false)
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{6}, true)
+	})
+}
+
+func TestRecoverMaterialized(t *testing.T) {
+	t.Run("should end GroupBy after a panic", func(t *testing.T) {
+		recovered := Recover(GroupBy(FromVals(1, 2, 3), func(x int) int {
+			y, _ := panicky(x)
+			return y
+		}))
+
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []Pair[int, []int]{}, true)
+	})
+
+	t.Run("should end SortBy after a panic and remove its runs", func(t *testing.T) {
+		dir := t.TempDir()
+		recovered := Recover(SortBy(Apply(FromVals(3, 1, 2, 4), panicky), cmp.Compare[int], SortOptions[int]{
+			MaxInMemory: 1,
+			TempDir:     dir,
+		}))
+
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []int{}, true)
+
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(entries))
+	})
+
+	t.Run("should end HashJoin after a panic", func(t *testing.T) {
+		recovered := Recover(HashJoin(FromVals(1, 2, 3), FromVals(1, 2, 3), identity, func(x int) int {
+			y, _ := panicky(x)
+			return y
+		}))
+
+		assertPanicked(t, recovered)
+		assertValues(t, recovered, []Pair[int, int]{}, true)
+	})
+}
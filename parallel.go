@@ -1,31 +1,39 @@
 package funky
 
-import "sync/atomic"
+import "sync"
 
 // Parallel applies a bound on the number of parallel called to
 // Next() will be run in parallel, even if the calls originate
 // from different goroutines. Passing 0 for n will cause
 // execution to occur serially. Passing any other value will
 // result in that number of additional simultaneous executions.
+//
+// Closing the iterator releases any calls that are waiting for
+// a spot in the pool, or for the source to produce a value.
 func Parallel[T any](it *Iter[T], n uint32) *Iter[T] {
 	queue := make(chan interface{}, n+1)
-	done := &atomic.Bool{}
+	stop := make(chan interface{})
 
 	return &Iter[T]{
 		next: func() (Elem[T], bool) {
-			if done.Load() {
+			// Claim a spot in the pool
+			select {
+			case queue <- nil:
+			case <-stop:
 				return DoneElem[T]()
 			}
 
-			// Claim a spot in the pool
-			queue <- nil
+			// Free up our spot in the pool once we're done
+			defer func() { <-queue }()
 
-			result := make(chan Elem[T])
+			// Buffered so the goroutine can finish even if we've
+			// stopped waiting for it.
+			result := make(chan Elem[T], 1)
 
 			go func() {
 				defer close(result)
 
-				elem, valid := it.Next()
+				elem, valid := catchPanic(it.Next)
 				if !valid {
 					return
 				}
@@ -33,18 +41,169 @@ func Parallel[T any](it *Iter[T], n uint32) *Iter[T] {
 				result <- elem
 			}()
 
-			elem, ok := <-result
-			if !ok {
+			select {
+			case elem, ok := <-result:
+				if !ok {
+					return DoneElem[T]()
+				}
+
+				return rethrow(elem, true)
+			case <-stop:
 				return DoneElem[T]()
 			}
+		},
+		close: func() {
+			close(stop)
+			it.Close()
+		},
+	}
+}
+
+// ParallelApplyOptions configures ParallelApply.
+type ParallelApplyOptions struct {
+	// Unordered allows results to be produced as soon as they are
+	// ready, rather than in the order of their inputs, so that one
+	// slow value can't hold up the rest.
+	Unordered bool
+}
+
+// ParallelApply is like Apply, but it runs the applier on a pool of
+// worker goroutines so that several values can be transformed at
+// once. This is useful for expensive appliers, such as those that
+// make network requests. Unless the options say otherwise, results
+// are produced in the same order as their inputs. Passing 0 for
+// workers is the same as passing 1.
+//
+// The source is read from a single goroutine, and no more than
+// twice as many values as there are workers will be read from it
+// before they are handed out by Next, which bounds the memory used
+// to hold results that are waiting on a slow predecessor.
+//
+// Example: pages := ParallelApply(urls, fetch, 8, ParallelApplyOptions{})
+func ParallelApply[I, O any](it *Iter[I], f Applier[I, O], workers uint32, opts ParallelApplyOptions) *Iter[O] {
+	if workers == 0 {
+		workers = 1
+	}
+
+	type job struct {
+		seq  uint64
+		elem Elem[I]
+	}
+
+	type result struct {
+		seq  uint64
+		elem Elem[O]
+	}
+
+	jobs := make(chan job)
+	results := make(chan result, workers)
+
+	// Each value read from the source holds a token until it has
+	// been handed out by Next, this is what bounds the number of
+	// values in flight.
+	tokens := make(chan interface{}, 2*workers)
+
+	// Closed when the iterator is closed so that all of the
+	// goroutines below, and any waiting callers, can give up.
+	stop := make(chan interface{})
+
+	// Read values from the source and hand them out to the workers,
+	// numbering them as we go so that the results can be put back
+	// in order.
+	go func() {
+		defer close(jobs)
+
+		for seq := uint64(0); ; seq++ {
+			select {
+			case tokens <- nil:
+			case <-stop:
+				return
+			}
+
+			elem, valid := catchPanic(it.Next)
+			if !valid {
+				return
+			}
+
+			select {
+			case jobs <- job{seq, elem}:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var workerGroup sync.WaitGroup
+	for range workers {
+		workerGroup.Add(1)
+
+		go func() {
+			defer workerGroup.Done()
+
+			for j := range jobs {
+				// A panic from the source is raised again here so
+				// that it is caught along with any from f.
+				elem, _ := catchPanic(func() (Elem[O], bool) {
+					inElem, _ := rethrow(j.elem, true)
+					return applyElem(inElem, f)
+				})
+
+				select {
+				case results <- result{j.seq, elem}:
+				case <-stop:
+					return
+				}
+			}
+		}()
+	}
+
+	// Once all the workers are finished, there won't be any more
+	// results, so we let Next know.
+	go func() {
+		workerGroup.Wait()
+		close(results)
+	}()
 
-			// Free up our spot in the pool
-			<-queue
+	// Results that arrived before their predecessors, keyed by
+	// sequence number, and the sequence number we need next.
+	pending := make(map[uint64]Elem[O])
+	var want uint64
+	var lock sync.Mutex
 
-			return elem, true
+	return &Iter[O]{
+		next: func() (Elem[O], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			for {
+				if elem, ok := pending[want]; ok {
+					delete(pending, want)
+					want++
+					<-tokens
+
+					return rethrow(elem, true)
+				}
+
+				select {
+				case r, more := <-results:
+					if !more {
+						return DoneElem[O]()
+					}
+
+					if opts.Unordered {
+						<-tokens
+						return rethrow(r.elem, true)
+					}
+
+					pending[r.seq] = r.elem
+				case <-stop:
+					return DoneElem[O]()
+				}
+			}
 		},
 		close: func() {
-			done.Store(true)
+			close(stop)
+			it.Close()
 		},
 	}
 }
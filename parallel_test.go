@@ -0,0 +1,120 @@
+package funky
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestParallel(t *testing.T) {
+	t.Run("should produce every value", func(t *testing.T) {
+		it := Parallel(makeFinite(3), 2)
+		assertValues(t, it, []int{0, 1, 2}, true)
+	})
+
+	t.Run("should release waiting calls on close", func(t *testing.T) {
+		block := make(chan interface{})
+		src := &Iter[int]{
+			next: func() (Elem[int], bool) {
+				<-block
+				return DoneElem[int]()
+			},
+		}
+
+		it := Parallel(src, 0)
+
+		var wg sync.WaitGroup
+		for range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				_, valid := it.Next()
+				assert.False(t, valid)
+			}()
+		}
+
+		closed := make(chan interface{})
+		go func() {
+			defer close(closed)
+			it.Close()
+		}()
+
+		// The source can only finish closing once the call that
+		// is stuck inside of it returns.
+		wg.Wait()
+		close(block)
+		<-closed
+
+		assertClosed(t, src)
+	})
+}
+
+func TestParallelApply(t *testing.T) {
+	slowly := func(v int) (int, error) {
+		// Make earlier values take longer so that they finish
+		// out of order.
+		time.Sleep(time.Duration(10-v%10) * time.Millisecond)
+		return v * 2, nil
+	}
+
+	t.Run("should preserve order", func(t *testing.T) {
+		it := ParallelApply(makeFinite(20), slowly, 4, ParallelApplyOptions{})
+		assertValues(t, it, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26, 28, 30, 32, 34, 36, 38}, true)
+	})
+
+	t.Run("should produce every value when unordered", func(t *testing.T) {
+		it := ParallelApply(makeFinite(10), slowly, 4, ParallelApplyOptions{Unordered: true})
+
+		values := it.ToSlice(100)
+		slices.Sort(values)
+		assert.Equal(t, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}, values)
+	})
+
+	t.Run("should run on several workers at once", func(t *testing.T) {
+		// Every call waits for all the others to start, so this
+		// can only finish if they all run at the same time.
+		var started sync.WaitGroup
+		started.Add(3)
+
+		it := ParallelApply(makeFinite(3), func(v int) (int, error) {
+			started.Done()
+			started.Wait()
+			return v, nil
+		}, 3, ParallelApplyOptions{})
+		assertValues(t, it, []int{0, 1, 2}, true)
+	})
+
+	t.Run("should pass along errors", func(t *testing.T) {
+		it := ParallelApply(makeFinite(3), func(v int) (int, error) {
+			if v == 1 {
+				return 0, errors.New("error")
+			}
+
+			return v, nil
+		}, 2, ParallelApplyOptions{})
+
+		assertValues(t, it, []int{0}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []int{2}, true)
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		src := makeInfinite()
+		it := ParallelApply(src, slowly, 2, ParallelApplyOptions{})
+		assertValues(t, it, []int{0}, false)
+
+		it.Close()
+
+		assertValues(t, it, []int{}, true)
+		assertClosed(t, src)
+	})
+}
@@ -0,0 +1,152 @@
+package funky
+
+import (
+	"errors"
+	"sync"
+)
+
+// An ErrorPolicy decides what an operation does when it comes
+// across an element that carries an error.
+type ErrorPolicy int
+
+const (
+	// PropagateErrors passes elements that carry an error along,
+	// untouched, which is what operators do unless told otherwise.
+	// Operations at the end of a pipeline, such as writers, have
+	// nowhere to pass them, so they treat it like StopOnError.
+	PropagateErrors ErrorPolicy = iota
+
+	// StopOnError ends the operation at the first error and
+	// reports it to the caller.
+	StopOnError
+
+	// SkipErrors ignores elements that carry an error and carries
+	// on with the rest.
+	SkipErrors
+
+	// CollectErrors carries on past elements that carry an error,
+	// like SkipErrors, then reports all of the errors, joined
+	// together, once the operation is complete.
+	CollectErrors
+
+	// DeadLetterErrors sends elements that carry an error to a
+	// DeadLetterSink, where they can be looked at later, and
+	// carries on with the rest.
+	DeadLetterErrors
+)
+
+// HandleErrors applies an error policy to the elements that carry
+// an error, leaving the values alone, so that any stage of a
+// pipeline can decide how its errors are treated by following it
+// with HandleErrors.
+//
+//   - PropagateErrors passes errors along, it changes nothing.
+//   - StopOnError passes the first error along, then ends, closing
+//     the source right away.
+//   - SkipErrors discards errors, like NoError.
+//   - CollectErrors discards errors as they come, then produces a
+//     single element, with all of them joined together, at the end.
+//   - DeadLetterErrors sends each error element, value and all, to
+//     sink, which must not be nil, instead of producing it. If the
+//     sink fails, the element is produced after all, carrying both
+//     errors, as it would be by ApplyWithDeadLetter.
+//
+// Dead letters are numbered by their position among all of the
+// elements HandleErrors has seen. Their stage is left empty, since
+// the error came from somewhere earlier in the pipeline.
+//
+// For example (in pseudocode):
+//
+//	HandleErrors({1, err1, 2, err2}, CollectErrors, nil) -> {1, 2, join(err1, err2)}
+func HandleErrors[T any](it *Iter[T], policy ErrorPolicy, sink DeadLetterSink[T]) *Iter[T] {
+	if policy == DeadLetterErrors && sink == nil {
+		panic("funky: DeadLetterErrors requires a dead letter sink")
+	}
+
+	var errs []error
+	var done bool
+	var seq uint64
+	var lock sync.Mutex
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			for !done {
+				elem, valid := it.Next()
+				if !valid {
+					done = true
+
+					if len(errs) > 0 {
+						return ErrElem[T](errors.Join(errs...))
+					}
+
+					break
+				}
+
+				seq++
+
+				if elem.err == nil {
+					return elem, true
+				}
+
+				switch policy {
+				case StopOnError:
+					done = true
+					it.Close()
+					return elem, true
+				case SkipErrors:
+					continue
+				case CollectErrors:
+					errs = append(errs, elem.err)
+					continue
+				case DeadLetterErrors:
+					if err := putDeadLetter(sink, "", seq-1, elem.val, elem.err); err != nil {
+						return Elem[T]{val: elem.val, err: err}, true
+					}
+
+					continue
+				default:
+					return elem, true
+				}
+			}
+
+			return DoneElem[T]()
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// Catch gives each element that carries an error to f, along with
+// its value, which may be a partial result, and produces whatever f
+// returns in its place. This allows errors to be replaced with a
+// fallback value, by returning a nil error, or to be changed, by
+// returning a different one. Values are passed along untouched.
+//
+// Example:
+//
+//	prices := Catch(Apply(items, lookupPrice), func(_ float64, err error) (float64, error) {
+//		if errors.Is(err, ErrNotFound) {
+//			return 0, nil
+//		}
+//
+//		return 0, err
+//	})
+func Catch[T any](it *Iter[T], f func(T, error) (T, error)) *Iter[T] {
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			elem, valid := it.Next()
+			if !valid || elem.err == nil {
+				return elem, valid
+			}
+
+			return ResultElem(f(elem.val, elem.err))
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
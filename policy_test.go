@@ -0,0 +1,136 @@
+package funky
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+// makeTwoErrors produces 1, the first error, 2, the second error,
+// then 3.
+func makeTwoErrors() *Iter[int] {
+	return makeFrom([]Elem[int]{
+		{val: 1},
+		{err: errors.New("first")},
+		{val: 2},
+		{val: 20, err: errors.New("second")},
+		{val: 3},
+	})
+}
+
+func TestHandleErrors(t *testing.T) {
+	t.Run("should propagate errors by default", func(t *testing.T) {
+		handled := HandleErrors(makeMixed(), PropagateErrors, nil)
+
+		assertValues(t, handled, []int{1}, false)
+		elem, valid := handled.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+		assertValues(t, handled, []int{2}, true)
+	})
+
+	t.Run("should stop after the first error", func(t *testing.T) {
+		source := makeTwoErrors()
+		handled := HandleErrors(source, StopOnError, nil)
+
+		assertValues(t, handled, []int{1}, false)
+		elem, valid := handled.Next()
+		assert.True(t, valid)
+		assert.EqualError(t, elem.err, "first")
+		assertValues(t, handled, []int{}, true)
+		assertClosed(t, source)
+	})
+
+	t.Run("should skip errors", func(t *testing.T) {
+		handled := HandleErrors(makeTwoErrors(), SkipErrors, nil)
+		assertValues(t, handled, []int{1, 2, 3}, true)
+	})
+
+	t.Run("should collect errors at the end", func(t *testing.T) {
+		handled := HandleErrors(makeTwoErrors(), CollectErrors, nil)
+
+		assertValues(t, handled, []int{1, 2, 3}, false)
+		elem, valid := handled.Next()
+		assert.True(t, valid)
+		assert.EqualError(t, elem.err, "first\nsecond")
+		assertValues(t, handled, []int{}, true)
+	})
+
+	t.Run("should not add an error when there are none to collect", func(t *testing.T) {
+		handled := HandleErrors(FromVals(1, 2), CollectErrors, nil)
+		assertValues(t, handled, []int{1, 2}, true)
+	})
+
+	t.Run("should dead letter errors", func(t *testing.T) {
+		var sink MemorySink[int]
+		handled := HandleErrors(makeTwoErrors(), DeadLetterErrors, &sink)
+		assertValues(t, handled, []int{1, 2, 3}, true)
+
+		letters := sink.Letters()
+		assert.Equal(t, 2, len(letters))
+		assert.Equal(t, uint64(1), letters[0].Seq)
+		assert.EqualError(t, letters[0].Err, "first")
+		assert.Equal(t, uint64(3), letters[1].Seq)
+		assert.Equal(t, 20, letters[1].Input)
+	})
+
+	t.Run("should produce errors the sink can't take", func(t *testing.T) {
+		failed := errors.New("failed")
+		handled := HandleErrors(makeMixed(), DeadLetterErrors, SinkFunc[int](func(DeadLetter[int]) error {
+			return failed
+		}))
+
+		assertValues(t, handled, []int{1}, false)
+		elem, valid := handled.Next()
+		assert.True(t, valid)
+		assert.IsError(t, elem.err, failed)
+		assertValues(t, handled, []int{2}, true)
+	})
+
+	t.Run("should require a dead letter sink", func(t *testing.T) {
+		assert.Panics(t, func() {
+			HandleErrors(makeMixed(), DeadLetterErrors, nil)
+		})
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		source := makeInfinite()
+		HandleErrors(source, SkipErrors, nil).Close()
+		assertClosed(t, source)
+	})
+}
+
+func TestCatch(t *testing.T) {
+	t.Run("should replace errors with a fallback", func(t *testing.T) {
+		caught := Catch(makeMixed(), func(_ int, err error) (int, error) {
+			return -1, nil
+		})
+
+		assertValues(t, caught, []int{1, -1, 2}, true)
+	})
+
+	t.Run("should change errors", func(t *testing.T) {
+		replaced := errors.New("replaced")
+		caught := Catch(makeTwoErrors(), func(v int, err error) (int, error) {
+			if v == 0 {
+				return 0, nil
+			}
+
+			return v, replaced
+		})
+
+		assertValues(t, caught, []int{1, 0, 2}, false)
+		elem, valid := caught.Next()
+		assert.True(t, valid)
+		assert.IsError(t, elem.err, replaced)
+		assert.Equal(t, 20, elem.val)
+		assertValues(t, caught, []int{3}, true)
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		source := makeInfinite()
+		Catch(source, func(v int, err error) (int, error) { return v, err }).Close()
+		assertClosed(t, source)
+	})
+}
@@ -0,0 +1,184 @@
+package funky
+
+import (
+	"sync"
+	"time"
+)
+
+// Limit configures RateLimit and RateLimitBy.
+type Limit struct {
+	// Rate is the number of values allowed per second, on average.
+	// It must be positive.
+	Rate float64
+
+	// Burst is the number of values allowed in quick succession
+	// after a quiet period. Zero means 1.
+	Burst uint32
+
+	// Clock is used to wait for values to be allowed. Nil means
+	// SystemClock.
+	Clock Clock
+}
+
+// RateLimit slows the iterator down so that it produces no more
+// than the limit's rate of values per second, on average, using a
+// token bucket. Each call to Next waits, if necessary, before it
+// pulls a value from the source, so nothing upstream runs early,
+// though this means the call that finds the source exhausted may
+// wait as well.
+//
+// Closing the iterator cuts short any waiting calls, which then
+// produce nothing. To stop waiting when a context ends, wrap the
+// result with WithContext.
+//
+// Example: requests := Apply(RateLimit(urls, Limit{Rate: 10, Burst: 5}), fetch)
+func RateLimit[T any](it *Iter[T], limit Limit) *Iter[T] {
+	bucket := newTokenBucket(limit)
+
+	stop := make(chan interface{})
+	var stopOnce sync.Once
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			if !bucket.wait(stop) {
+				return DoneElem[T]()
+			}
+
+			return it.Next()
+		},
+		close: func() {
+			stopOnce.Do(func() {
+				close(stop)
+			})
+
+			it.Close()
+		},
+	}
+}
+
+// RateLimitBy is like RateLimit, but each key gets its own limit,
+// so a busy key doesn't hold up the others when Next is called from
+// several goroutines. Since the key comes from the value, each call
+// to Next pulls a value first, then waits. Errors are passed along
+// without waiting.
+//
+// A value that is waiting when the iterator is closed is dropped.
+// A token bucket is kept for every key seen, so keys should come
+// from a reasonably small set.
+//
+// Example: calls := RateLimitBy(requests, func(r Request) string { return r.Tenant }, Limit{Rate: 1})
+func RateLimitBy[T any, K comparable](it *Iter[T], key KeyFunc[T, K], limit Limit) *Iter[T] {
+	buckets := map[K]*tokenBucket{}
+	var lock sync.Mutex
+
+	stop := make(chan interface{})
+	var stopOnce sync.Once
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			elem, valid := it.Next()
+			if !valid || elem.err != nil {
+				return elem, valid
+			}
+
+			k := key(elem.val)
+
+			lock.Lock()
+			bucket, ok := buckets[k]
+			if !ok {
+				bucket = newTokenBucket(limit)
+				buckets[k] = bucket
+			}
+			lock.Unlock()
+
+			if !bucket.wait(stop) {
+				return DoneElem[T]()
+			}
+
+			return elem, true
+		},
+		close: func() {
+			stopOnce.Do(func() {
+				close(stop)
+			})
+
+			it.Close()
+		},
+	}
+}
+
+// tokenBucket holds up to burst tokens and gains rate tokens each
+// second. Taking a token when there are none puts the bucket into
+// debt, which tells the taker how long to wait, and makes later
+// takers wait behind it.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	clock  Clock
+	tokens float64
+	last   time.Time
+	lock   sync.Mutex
+}
+
+func newTokenBucket(limit Limit) *tokenBucket {
+	if limit.Rate <= 0 {
+		panic("funky: rate limit must be positive")
+	}
+
+	burst := float64(max(limit.Burst, 1))
+
+	clock := limit.Clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return &tokenBucket{
+		rate:   limit.Rate,
+		burst:  burst,
+		clock:  clock,
+		tokens: burst,
+		last:   clock.Now(),
+	}
+}
+
+// reserve takes a token and returns how long to wait before using
+// it.
+func (b *tokenBucket) reserve() time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	now := b.clock.Now()
+	if now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+	}
+
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// wait takes a token and waits until it can be used. It returns
+// false if the stop channel was closed first.
+func (b *tokenBucket) wait(stop <-chan interface{}) bool {
+	select {
+	case <-stop:
+		return false
+	default:
+	}
+
+	d := b.reserve()
+	if d <= 0 {
+		return true
+	}
+
+	select {
+	case <-b.clock.After(d):
+		return true
+	case <-stop:
+		return false
+	}
+}
@@ -0,0 +1,113 @@
+package funky
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Run("should allow a burst then wait", func(t *testing.T) {
+		clock := &fakeClock{}
+		limited := RateLimit(makeInfinite(), Limit{Rate: 1, Burst: 2, Clock: clock})
+
+		assertValues(t, limited, []int{0, 1, 2, 3, 4}, false)
+		assert.Equal(t, []time.Duration{time.Second, time.Second, time.Second}, clock.waits)
+	})
+
+	t.Run("should wait a fraction of a second for fast rates", func(t *testing.T) {
+		clock := &fakeClock{}
+		limited := RateLimit(makeInfinite(), Limit{Rate: 4, Clock: clock})
+
+		assertValues(t, limited, []int{0, 1, 2}, false)
+		assert.Equal(t, []time.Duration{time.Second / 4, time.Second / 4}, clock.waits)
+	})
+
+	t.Run("should refill after a quiet period", func(t *testing.T) {
+		clock := &fakeClock{}
+		limited := RateLimit(makeInfinite(), Limit{Rate: 1, Burst: 2, Clock: clock})
+
+		assertValues(t, limited, []int{0, 1}, false)
+		clock.now = clock.now.Add(time.Minute)
+		assertValues(t, limited, []int{2, 3}, false)
+		assert.Equal(t, 0, len(clock.waits))
+	})
+
+	t.Run("should stop waiting when closed", func(t *testing.T) {
+		source := makeInfinite()
+		clock := stalledClock{waiting: make(chan interface{})}
+		limited := RateLimit(source, Limit{Rate: 1, Clock: clock})
+
+		assertValues(t, limited, []int{0}, false)
+
+		result := make(chan bool)
+		go func() {
+			_, valid := limited.Next()
+			result <- valid
+		}()
+
+		<-clock.waiting
+		go limited.Close()
+
+		assert.False(t, <-result)
+		assertClosed(t, source)
+	})
+
+	t.Run("should require a positive rate", func(t *testing.T) {
+		assert.Panics(t, func() {
+			RateLimit(makeInfinite(), Limit{})
+		})
+	})
+}
+
+func TestRateLimitBy(t *testing.T) {
+	t.Run("should limit each key separately", func(t *testing.T) {
+		clock := &fakeClock{}
+		limited := RateLimitBy(FromVals("a", "b", "a", "b"), letter, Limit{Rate: 1, Clock: clock})
+
+		assertValues(t, limited, []string{"a", "b", "a", "b"}, true)
+		assert.Equal(t, []time.Duration{time.Second}, clock.waits)
+	})
+
+	t.Run("should pass errors along without waiting", func(t *testing.T) {
+		clock := &fakeClock{}
+		limited := RateLimitBy(fromElems([]Elem[int]{
+			{err: errors.New("error")},
+			{err: errors.New("error")},
+		}), identity, Limit{Rate: 1, Clock: clock})
+
+		for range 2 {
+			elem, valid := limited.Next()
+			assert.True(t, valid)
+			assert.Error(t, elem.err)
+		}
+
+		assert.Equal(t, 0, len(clock.waits))
+	})
+
+	t.Run("should stop waiting when closed", func(t *testing.T) {
+		source := makeConstant("a")
+		clock := stalledClock{waiting: make(chan interface{})}
+		limited := RateLimitBy(source, letter, Limit{Rate: 1, Clock: clock})
+
+		assertValues(t, limited, []string{"a"}, false)
+
+		result := make(chan bool)
+		go func() {
+			_, valid := limited.Next()
+			result <- valid
+		}()
+
+		<-clock.waiting
+		go limited.Close()
+
+		assert.False(t, <-result)
+		assertClosed(t, source)
+	})
+}
+
+func letter(s string) string {
+	return s
+}
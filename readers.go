@@ -0,0 +1,362 @@
+package funky
+
+import (
+	"bufio"
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// maxLineLength is the longest line that FromLines and
+// FromJSONLines can handle. Longer lines end the iterator
+// with an error.
+const maxLineLength = 1 << 20
+
+// FromLines creates an iterator that produces each line of text
+// read from r, without its line ending. If reading fails, the
+// error is produced as a final element, along with the number of
+// the line that couldn't be read.
+//
+// Closing the iterator closes r, if it is an io.Closer.
+func FromLines(r io.Reader) *Iter[string] {
+	scanner := newLineScanner(r)
+	line := 0
+	failed := false
+
+	return fromReader(r, func() (Elem[string], bool) {
+		if failed {
+			return DoneElem[string]()
+		}
+
+		if scanner.Scan() {
+			line++
+			return ValElem(scanner.Text())
+		}
+
+		if err := scanner.Err(); err != nil {
+			failed = true
+			return ErrElem[string](fmt.Errorf("lines read error: line %d: %w", line+1, err))
+		}
+
+		return DoneElem[string]()
+	})
+}
+
+// FromJSONLines creates an iterator that decodes a JSON value of
+// type T from each line read from r, skipping blank lines. Lines
+// that can't be decoded produce an error that includes the line
+// number, and iteration continues with the next line.
+//
+// Closing the iterator closes r, if it is an io.Closer.
+func FromJSONLines[T any](r io.Reader) *Iter[T] {
+	scanner := newLineScanner(r)
+	line := 0
+	failed := false
+
+	return fromReader(r, func() (Elem[T], bool) {
+		for !failed {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					failed = true
+					return ErrElem[T](fmt.Errorf("json lines read error: line %d: %w", line+1, err))
+				}
+
+				break
+			}
+
+			line++
+
+			text := scanner.Bytes()
+			if len(strings.TrimSpace(string(text))) == 0 {
+				continue
+			}
+
+			var v T
+			if err := json.Unmarshal(text, &v); err != nil {
+				return ErrElem[T](fmt.Errorf("json lines parse error: line %d: %w", line, err))
+			}
+
+			return ValElem(v)
+		}
+
+		return DoneElem[T]()
+	})
+}
+
+// CSVOptions configures FromCSV and FromCSVStructs. The zero value
+// reads standard, comma-separated values.
+type CSVOptions struct {
+	// Comma is the field delimiter, it defaults to ','.
+	Comma rune
+
+	// Comment, if not zero, marks lines that should be ignored
+	// when it appears at the beginning of the line.
+	Comment rune
+
+	// LazyQuotes allows quotes to appear in unquoted fields and
+	// non-doubled quotes to appear in quoted fields.
+	LazyQuotes bool
+
+	// SkipHeader discards the first record. FromCSVStructs always
+	// treats the first record as a header, so it ignores this.
+	SkipHeader bool
+}
+
+func (o CSVOptions) reader(r io.Reader) *csv.Reader {
+	cr := csv.NewReader(r)
+	if o.Comma != 0 {
+		cr.Comma = o.Comma
+	}
+	cr.Comment = o.Comment
+	cr.LazyQuotes = o.LazyQuotes
+
+	return cr
+}
+
+// FromCSV creates an iterator that produces each record read from
+// r as a slice of fields. Every record must have the same number of
+// fields as the first one.
+//
+// A record that can't be parsed produces an error that includes
+// its line number, and iteration continues with the next record.
+// If the record was read, but had the wrong number of fields, it
+// is produced along with the error.
+//
+// Closing the iterator closes r, if it is an io.Closer.
+func FromCSV(r io.Reader, opts CSVOptions) *Iter[[]string] {
+	cr := opts.reader(r)
+	skip := opts.SkipHeader
+	failed := false
+
+	return fromReader(r, func() (Elem[[]string], bool) {
+		for !failed {
+			record, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if skip {
+				skip = false
+				continue
+			}
+
+			if err != nil {
+				return csvError(record, err, &failed)
+			}
+
+			return ValElem(record)
+		}
+
+		return DoneElem[[]string]()
+	})
+}
+
+// FromCSVStructs creates an iterator that decodes each record read
+// from r into a struct of type T, which must be a struct type. The
+// first record is treated as a header, and each column is stored in
+// the exported field with a matching `csv` tag or, failing that, a
+// matching name, ignoring case. Columns without a matching field,
+// and fields tagged `csv:"-"`, are skipped.
+//
+// Fields may be strings, booleans, integers, floats, or types that
+// implement encoding.TextUnmarshaler. Records that can't be parsed
+// or decoded produce errors the same way as they do for FromCSV.
+//
+// Example:
+//
+//	type Person struct {
+//		Name string `csv:"name"`
+//		Age  int    `csv:"age"`
+//	}
+//
+//	people := FromCSVStructs[Person](file, CSVOptions{})
+func FromCSVStructs[T any](r io.Reader, opts CSVOptions) *Iter[T] {
+	structType := reflect.TypeFor[T]()
+	if structType.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("funky: FromCSVStructs requires a struct type, got %s", structType))
+	}
+
+	cr := opts.reader(r)
+	var columns []int
+	failed := false
+
+	return fromReader(r, func() (Elem[T], bool) {
+		if columns == nil && !failed {
+			header, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				failed = true
+			} else if err != nil {
+				failed = true
+				return ErrElem[T](fmt.Errorf("csv parse error: %w", err))
+			} else {
+				columns = csvColumns(structType, header)
+			}
+		}
+
+		for !failed {
+			record, err := cr.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				elem, valid := csvError(record, err, &failed)
+				if record == nil {
+					return Elem[T]{err: elem.err}, valid
+				}
+
+				err = elem.err
+			}
+
+			var v T
+			decodeErr := csvDecode(reflect.ValueOf(&v).Elem(), columns, record)
+			if decodeErr != nil {
+				line, _ := cr.FieldPos(0)
+				decodeErr = fmt.Errorf("csv decode error: line %d: %w", line, decodeErr)
+			}
+
+			if err = errors.Join(err, decodeErr); err != nil {
+				return Elem[T]{val: v, err: err}, true
+			}
+
+			return ValElem(v)
+		}
+
+		return DoneElem[T]()
+	})
+}
+
+// csvError turns an error from a CSV reader into an element. Parse
+// errors only affect a single record, but anything else, like an
+// I/O error, means nothing more can be read, so failed is set.
+func csvError(record []string, err error, failed *bool) (Elem[[]string], bool) {
+	var parseErr *csv.ParseError
+	if !errors.As(err, &parseErr) {
+		*failed = true
+		return ErrElem[[]string](fmt.Errorf("csv read error: %w", err))
+	}
+
+	return Elem[[]string]{
+		val: record,
+		err: fmt.Errorf("csv parse error: %w", err),
+	}, true
+}
+
+// csvColumns maps each column in the header to the index of the
+// struct field it should be stored in, or -1 if it should be
+// skipped.
+func csvColumns(structType reflect.Type, header []string) []int {
+	columns := make([]int, len(header))
+	for i, name := range header {
+		columns[i] = -1
+
+		for j := range structType.NumField() {
+			field := structType.Field(j)
+			if !field.IsExported() {
+				continue
+			}
+
+			fieldName := field.Name
+			if tag, ok := field.Tag.Lookup("csv"); ok {
+				fieldName = tag
+			}
+
+			if fieldName != "-" && strings.EqualFold(fieldName, strings.TrimSpace(name)) {
+				columns[i] = j
+				break
+			}
+		}
+	}
+
+	return columns
+}
+
+func csvDecode(v reflect.Value, columns []int, record []string) error {
+	var errs error
+	for i, text := range record {
+		if i >= len(columns) || columns[i] < 0 {
+			continue
+		}
+
+		field := v.Field(columns[i])
+		if err := csvSetField(field, text); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("field %s: %w", v.Type().Field(columns[i]).Name, err))
+		}
+	}
+
+	return errs
+}
+
+func csvSetField(field reflect.Value, text string) error {
+	if u, ok := field.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(text))
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(text, 10, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, field.Type().Bits())
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+
+	return nil
+}
+
+func newLineScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineLength)
+
+	return scanner
+}
+
+// fromReader creates an iterator around a function that reads one
+// element at a time from r. Calls to read are serialized, and r is
+// closed when the iterator is closed, if it is an io.Closer.
+func fromReader[T any](r io.Reader, read func() (Elem[T], bool)) *Iter[T] {
+	var lock sync.Mutex
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			return read()
+		},
+		close: func() {
+			if closer, ok := r.(io.Closer); ok {
+				_ = closer.Close()
+			}
+		},
+	}
+}
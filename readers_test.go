@@ -0,0 +1,145 @@
+package funky
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestFromLines(t *testing.T) {
+	t.Run("should produce each line", func(t *testing.T) {
+		it := FromLines(strings.NewReader("a\nb\r\n\nc"))
+		assertValues(t, it, []string{"a", "b", "", "c"}, true)
+	})
+
+	t.Run("should report read errors with a line number", func(t *testing.T) {
+		r := io.MultiReader(strings.NewReader("a\n"), &failingReader{})
+		it := FromLines(r)
+		assertValues(t, it, []string{"a"}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.IsError(t, elem.err, errRead)
+		assert.Contains(t, elem.err.Error(), "line 2")
+
+		_, valid = it.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should close the reader", func(t *testing.T) {
+		r := &closingReader{Reader: strings.NewReader("a\n")}
+		FromLines(r).Close()
+		assert.True(t, r.closed)
+	})
+}
+
+func TestFromJSONLines(t *testing.T) {
+	type record struct {
+		Name string `json:"name"`
+	}
+
+	t.Run("should decode each line", func(t *testing.T) {
+		it := FromJSONLines[record](strings.NewReader("{\"name\":\"a\"}\n\n{\"name\":\"b\"}\n"))
+		assertValues(t, it, []record{{"a"}, {"b"}}, true)
+	})
+
+	t.Run("should continue past bad lines", func(t *testing.T) {
+		it := FromJSONLines[record](strings.NewReader("{\"name\":\"a\"}\n{oops\n{\"name\":\"b\"}\n"))
+		assertValues(t, it, []record{{"a"}}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+		assert.Contains(t, elem.err.Error(), "line 2")
+
+		assertValues(t, it, []record{{"b"}}, true)
+	})
+
+	t.Run("should close the reader", func(t *testing.T) {
+		r := &closingReader{Reader: strings.NewReader("")}
+		FromJSONLines[record](r).Close()
+		assert.True(t, r.closed)
+	})
+}
+
+func TestFromCSV(t *testing.T) {
+	t.Run("should produce each record", func(t *testing.T) {
+		it := FromCSV(strings.NewReader("a,b\n1,\"2,3\"\n"), CSVOptions{})
+		assertValues(t, it, [][]string{{"a", "b"}, {"1", "2,3"}}, true)
+	})
+
+	t.Run("should apply options", func(t *testing.T) {
+		it := FromCSV(strings.NewReader("a;b\n# note\n1;2\n"), CSVOptions{
+			Comma:      ';',
+			Comment:    '#',
+			SkipHeader: true,
+		})
+		assertValues(t, it, [][]string{{"1", "2"}}, true)
+	})
+
+	t.Run("should continue past bad records", func(t *testing.T) {
+		it := FromCSV(strings.NewReader("a,b\n1,2,3\n4,5\n"), CSVOptions{})
+		assertValues(t, it, [][]string{{"a", "b"}}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Equal(t, []string{"1", "2", "3"}, elem.val)
+		assert.Contains(t, elem.err.Error(), "line 2")
+
+		assertValues(t, it, [][]string{{"4", "5"}}, true)
+	})
+}
+
+func TestFromCSVStructs(t *testing.T) {
+	type person struct {
+		Name    string `csv:"name"`
+		Age     int
+		Ignored string `csv:"-"`
+	}
+
+	t.Run("should decode each record", func(t *testing.T) {
+		it := FromCSVStructs[person](strings.NewReader("AGE,name,extra\n30,a,x\n40,b,y\n"), CSVOptions{})
+		assertValues(t, it, []person{
+			{Name: "a", Age: 30},
+			{Name: "b", Age: 40},
+		}, true)
+	})
+
+	t.Run("should report decode errors with a line number", func(t *testing.T) {
+		it := FromCSVStructs[person](strings.NewReader("name,age\na,old\nb,40\n"), CSVOptions{})
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Equal(t, "a", elem.val.Name)
+		assert.Contains(t, elem.err.Error(), "line 2")
+		assert.Contains(t, elem.err.Error(), "Age")
+
+		assertValues(t, it, []person{{Name: "b", Age: 40}}, true)
+	})
+
+	t.Run("should handle an empty input", func(t *testing.T) {
+		it := FromCSVStructs[person](strings.NewReader(""), CSVOptions{})
+		assertValues(t, it, []person{}, true)
+	})
+}
+
+var errRead = errors.New("read error")
+
+type failingReader struct{}
+
+func (r *failingReader) Read(_ []byte) (int, error) {
+	return 0, errRead
+}
+
+type closingReader struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closingReader) Close() error {
+	r.closed = true
+	return nil
+}
@@ -1,5 +1,10 @@
 package funky
 
+import (
+	"errors"
+	"sync"
+)
+
 // A Reducer accepts two values and incorporates the second one
 // into the first, which represents some kind of aggregate of
 // many values.
@@ -14,7 +19,17 @@ type Reducer[I, A any] func(A, I) (A, error)
 // or returns an error. If the reducer function returns an error,
 // the accumulator will be returned, along with the error.
 func Reduce[I any, A any](it *Iter[I], f Reducer[I, A]) (A, error) {
-	acc := *new(A)
+	return Fold(it, *new(A), f)
+}
+
+// Fold is like Reduce, but the accumulator starts out as init,
+// rather than the zero value.
+//
+// For example (in pseudocode):
+//
+//	Fold({1, 2, 3}, 10, (sum, x) -> sum + x) -> 16
+func Fold[I any, A any](it *Iter[I], init A, f Reducer[I, A]) (A, error) {
+	acc := init
 	var err error
 
 	for elem, valid := it.Next(); valid; elem, valid = it.Next() {
@@ -30,3 +45,135 @@ func Reduce[I any, A any](it *Iter[I], f Reducer[I, A]) (A, error) {
 
 	return acc, nil
 }
+
+// ReduceOptions configures ParallelReduce.
+type ReduceOptions struct {
+	// OnError decides what happens to elements that carry an
+	// error. By default, the reduction stops at the first one, like
+	// Reduce. Under CollectErrors, the remaining values are reduced
+	// and the errors are returned, joined together, along with the
+	// result. There is nowhere to set errors aside, so
+	// DeadLetterErrors stops as well, use HandleErrors ahead of the
+	// reduction instead.
+	OnError ErrorPolicy
+}
+
+// ParallelReduce splits a reduction across several goroutines.
+// Each worker pulls values from the iterator and reduces them into
+// its own accumulator, starting from the zero value, then the
+// partial accumulators are merged, in order, using combine.
+//
+// Since values are spread across the workers in no particular
+// order, the reducer and combiner should be associative, and the
+// reducer should be commutative, so that the result doesn't depend
+// on which worker saw which value. Passing 0 or 1 for workers
+// reduces the values in order on a single goroutine.
+//
+// If the reducer or combiner returns an error, the reduction stops
+// and the error is returned along with whatever was combined. If
+// the reducer, or the source, panics, the reduction stops and the
+// panic is raised again, as a *PanicError, on the calling goroutine.
+//
+// Example:
+//
+//	total, err := ParallelReduce(values, 8, add, add, ReduceOptions{})
+func ParallelReduce[I, A any](it *Iter[I], workers uint32, f Reducer[I, A], combine func(A, A) (A, error), opts ReduceOptions) (A, error) {
+	if workers == 0 {
+		workers = 1
+	}
+
+	partials := make([]A, workers)
+	collected := make([][]error, workers)
+
+	// The first error that stops the reduction, the stop channel is
+	// closed at the same time to let the other workers know.
+	var stopErr error
+	var stopOnce sync.Once
+	stop := make(chan interface{})
+	fail := func(err error) {
+		stopOnce.Do(func() {
+			stopErr = err
+			close(stop)
+		})
+	}
+
+	var workerGroup sync.WaitGroup
+	for w := range workers {
+		workerGroup.Add(1)
+
+		go func() {
+			defer workerGroup.Done()
+
+			acc := *new(A)
+			defer func() { partials[w] = acc }()
+
+			// A panic here would crash the program, so it stops
+			// the reduction, to be raised again by the caller.
+			defer func() {
+				if p := recover(); p != nil {
+					fail(caughtPanic{newPanicError(p)})
+				}
+			}()
+
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				elem, valid := it.Next()
+				if !valid {
+					return
+				}
+
+				if elem.err != nil {
+					switch opts.OnError {
+					case SkipErrors:
+						continue
+					case CollectErrors:
+						collected[w] = append(collected[w], elem.err)
+						continue
+					default:
+						fail(elem.err)
+						return
+					}
+				}
+
+				next, err := f(acc, elem.val)
+				if err != nil {
+					fail(err)
+					return
+				}
+
+				acc = next
+			}
+		}()
+	}
+
+	workerGroup.Wait()
+
+	if p, ok := stopErr.(caughtPanic); ok {
+		panic(p.PanicError)
+	}
+
+	result := partials[0]
+	for _, partial := range partials[1:] {
+		var err error
+		result, err = combine(result, partial)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if stopErr != nil {
+		return result, stopErr
+	}
+
+	var errs []error
+	for _, workerErrs := range collected {
+		errs = append(errs, workerErrs...)
+	}
+
+	return result, errors.Join(errs...)
+}
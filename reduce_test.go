@@ -0,0 +1,103 @@
+package funky
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func add(sum, v int) (int, error) {
+	return sum + v, nil
+}
+
+func TestReduce(t *testing.T) {
+	t.Run("should start from the zero value", func(t *testing.T) {
+		total, err := Reduce(FromVals(1, 2, 3), add)
+		assert.NoError(t, err)
+		assert.Equal(t, 6, total)
+	})
+
+	t.Run("should stop at the first error", func(t *testing.T) {
+		total, err := Reduce(makeMixed(), add)
+		assert.Error(t, err)
+		assert.Equal(t, 1, total)
+	})
+}
+
+func TestFold(t *testing.T) {
+	t.Run("should start from the initial value", func(t *testing.T) {
+		total, err := Fold(FromVals(1, 2, 3), 10, add)
+		assert.NoError(t, err)
+		assert.Equal(t, 16, total)
+	})
+}
+
+func TestParallelReduce(t *testing.T) {
+	// The workers share the source, so it has to be safe to pull
+	// from concurrently, which rules out most of the test helpers.
+	count := func(n int) *Iter[int] {
+		elems := make([]Elem[int], n)
+		for i := range elems {
+			elems[i] = Elem[int]{val: i}
+		}
+
+		return fromElems(elems)
+	}
+
+	mixed := func() *Iter[int] {
+		return fromElems([]Elem[int]{
+			{val: 1},
+			{err: errors.New("error")},
+			{val: 2},
+		})
+	}
+
+	t.Run("should combine partial results", func(t *testing.T) {
+		total, err := ParallelReduce(count(1001), 4, add, add, ReduceOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, 500500, total)
+	})
+
+	t.Run("should reduce in order with one worker", func(t *testing.T) {
+		joined, err := ParallelReduce(FromVals("a", "b", "c"), 1, func(acc, v string) (string, error) {
+			return acc + v, nil
+		}, nil, ReduceOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", joined)
+	})
+
+	t.Run("should stop at the first error by default", func(t *testing.T) {
+		_, err := ParallelReduce(makeMixed(), 1, add, add, ReduceOptions{})
+		assert.EqualError(t, err, "error")
+	})
+
+	t.Run("should stop at errors it can't set aside", func(t *testing.T) {
+		_, err := ParallelReduce(mixed(), 1, add, add, ReduceOptions{OnError: DeadLetterErrors})
+		assert.EqualError(t, err, "error")
+	})
+
+	t.Run("should skip errors", func(t *testing.T) {
+		total, err := ParallelReduce(mixed(), 2, add, add, ReduceOptions{OnError: SkipErrors})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, total)
+	})
+
+	t.Run("should collect errors", func(t *testing.T) {
+		total, err := ParallelReduce(mixed(), 2, add, add, ReduceOptions{OnError: CollectErrors})
+		assert.EqualError(t, err, "error")
+		assert.Equal(t, 3, total)
+	})
+
+	t.Run("should stop when the reducer fails", func(t *testing.T) {
+		failure := errors.New("failure")
+		_, err := ParallelReduce(count(1000), 4, func(sum, v int) (int, error) {
+			if v > 100 {
+				return sum, failure
+			}
+
+			return sum + v, nil
+		}, add, ReduceOptions{})
+		assert.IsError(t, err, failure)
+	})
+}
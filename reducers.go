@@ -1,14 +1,242 @@
 package funky
 
-import "cmp"
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
 
 func Count[I any, A uint64](m A, _ I) (A, error) {
 	m++
 	return m, nil
 }
 
-// todo: create a histogram type of some sort for R
+// A Histogram counts values by bucket. Each bucket is identified
+// by its lower bound, which is the smallest value that could fall
+// into it. Use one of the constructors to choose how values are
+// bucketed, then use Reducer to fill the histogram with Reduce.
+//
+// Reduce produces a nil histogram from an empty input, so the
+// methods that query a histogram treat nil as an empty histogram.
+//
+// Example:
+//
+//	h, err := Reduce(ages, WidthHistogram(10).Reducer())
+type Histogram[T cmp.Ordered] struct {
+	// bucket maps a value to the lower bound of the bucket that
+	// it belongs in.
+	bucket func(T) T
+	counts map[T]uint64
+	total  uint64
+}
+
+// A Bucket is a single bar in a Histogram.
+type Bucket[T cmp.Ordered] struct {
+	Low   T
+	Count uint64
+}
+
+// ExactHistogram creates a histogram that counts each distinct
+// value separately. This is the right choice for discrete values,
+// like categories or small integers.
+func ExactHistogram[T cmp.Ordered]() *Histogram[T] {
+	return newHistogram(func(v T) T {
+		return v
+	})
+}
+
+// WidthHistogram creates a histogram with buckets of the given
+// width, aligned to zero, so that a value v falls into the bucket
+// [k * width, (k + 1) * width) for some integer k. The width must
+// be positive.
+//
+// Bucket bounds are computed using float64 arithmetic, so very
+// large 64-bit integers may be placed imprecisely.
+func WidthHistogram[T Number](width T) *Histogram[T] {
+	if width <= 0 {
+		panic("funky: histogram width must be positive")
+	}
+
+	return newHistogram(func(v T) T {
+		return T(math.Floor(float64(v)/float64(width))) * width
+	})
+}
+
+// BoundsHistogram creates a histogram with a bucket starting at
+// each of the given boundaries, so each bucket holds values from
+// its boundary up to, but not including, the next one. The last
+// bucket is unbounded above. Values less than the smallest
+// boundary are counted in the first bucket.
+//
+// The boundaries need not be sorted. If none are given, the
+// histogram behaves like ExactHistogram.
+func BoundsHistogram[T cmp.Ordered](bounds ...T) *Histogram[T] {
+	if len(bounds) == 0 {
+		return ExactHistogram[T]()
+	}
+
+	bounds = slices.Clone(bounds)
+	slices.Sort(bounds)
+	bounds = slices.Compact(bounds)
+
+	return newHistogram(func(v T) T {
+		i, found := slices.BinarySearch(bounds, v)
+		if found || i == 0 {
+			return bounds[i]
+		}
+
+		return bounds[i-1]
+	})
+}
+
+func newHistogram[T cmp.Ordered](bucket func(T) T) *Histogram[T] {
+	return &Histogram[T]{
+		bucket: bucket,
+		counts: make(map[T]uint64),
+	}
+}
+
+// Add counts a single value.
+func (h *Histogram[T]) Add(v T) {
+	h.counts[h.bucket(v)]++
+	h.total++
+}
+
+// Merge adds the counts from other, which should bucket its values
+// the same way as h, to h.
+func (h *Histogram[T]) Merge(other *Histogram[T]) {
+	for low, count := range other.counts {
+		h.counts[low] += count
+	}
+
+	h.total += other.total
+}
+
+// Reducer returns a reducer that adds each value to a histogram.
+// Since Reduce starts with a nil accumulator, the reducer begins
+// with a new, empty histogram that buckets values the same way as
+// h, which is left alone. This means that the reducer can be shared
+// by the workers of ParallelReduce.
+func (h *Histogram[T]) Reducer() Reducer[T, *Histogram[T]] {
+	return func(acc *Histogram[T], v T) (*Histogram[T], error) {
+		if acc == nil {
+			acc = newHistogram(h.bucket)
+		}
+
+		acc.Add(v)
+		return acc, nil
+	}
+}
+
+// Combiner returns a function that merges two histograms, for use
+// with ParallelReduce. Either histogram may be nil, which happens
+// when a worker doesn't see any values, and if both are, so is the
+// result.
+//
+// Example:
+//
+//	h, err := ParallelReduce(ages, 8, hist.Reducer(), hist.Combiner(), ReduceOptions{})
+func (h *Histogram[T]) Combiner() func(*Histogram[T], *Histogram[T]) (*Histogram[T], error) {
+	return func(a, b *Histogram[T]) (*Histogram[T], error) {
+		if a == nil {
+			return b, nil
+		}
+
+		if b != nil {
+			a.Merge(b)
+		}
+
+		return a, nil
+	}
+}
+
+// Count returns the number of values in the bucket that v would
+// fall into.
+func (h *Histogram[T]) Count(v T) uint64 {
+	if h == nil {
+		return 0
+	}
+
+	return h.counts[h.bucket(v)]
+}
+
+// Total returns the number of values that have been added.
+func (h *Histogram[T]) Total() uint64 {
+	if h == nil {
+		return 0
+	}
+
+	return h.total
+}
+
+// Buckets returns the non-empty buckets, ordered by lower bound.
+func (h *Histogram[T]) Buckets() []Bucket[T] {
+	if h == nil {
+		return []Bucket[T]{}
+	}
+
+	buckets := make([]Bucket[T], 0, len(h.counts))
+	for low, count := range h.counts {
+		buckets = append(buckets, Bucket[T]{Low: low, Count: count})
+	}
+
+	slices.SortFunc(buckets, func(a, b Bucket[T]) int {
+		return cmp.Compare(a.Low, b.Low)
+	})
+
+	return buckets
+}
+
+// Percentile returns the lower bound of the bucket that contains
+// the p-th percentile value, where p is between 0 and 100, using
+// the nearest-rank method. For an exact histogram, this is the
+// percentile value itself. If the histogram is empty, the second
+// return value will be false.
+func (h *Histogram[T]) Percentile(p float64) (T, bool) {
+	if h.Total() == 0 {
+		return *new(T), false
+	}
+
+	p = min(max(p, 0), 100)
+	rank := max(uint64(math.Ceil(p/100*float64(h.total))), 1)
+
+	buckets := h.Buckets()
+	var seen uint64
+	for _, b := range buckets {
+		seen += b.Count
+		if seen >= rank {
+			return b.Low, true
+		}
+	}
+
+	return buckets[len(buckets)-1].Low, true
+}
+
+// histogramWidth is the length, in characters, of the longest bar
+// rendered by String.
+const histogramWidth = 40
+
+// String renders the histogram as text, one bucket per line, with
+// a bar proportional to the bucket's count.
+func (h *Histogram[T]) String() string {
+	buckets := h.Buckets()
+
+	labels := make([]string, len(buckets))
+	labelWidth := 0
+	var most uint64
+	for i, b := range buckets {
+		labels[i] = fmt.Sprint(b.Low)
+		labelWidth = max(labelWidth, len(labels[i]))
+		most = max(most, b.Count)
+	}
+
+	var sb strings.Builder
+	for i, b := range buckets {
+		bar := max(int(b.Count*histogramWidth/most), 1)
+		_, _ = fmt.Fprintf(&sb, "%*s | %s %d\n", labelWidth, labels[i], strings.Repeat("#", bar), b.Count)
+	}
 
-func Histogram[I cmp.Ordered, A any](h A, t I) (A, error) {
-	panic("not implemented")
+	return sb.String()
 }
@@ -0,0 +1,148 @@
+package funky
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestHistogram(t *testing.T) {
+	t.Run("should count exact values", func(t *testing.T) {
+		h, err := Reduce(FromVals("a", "b", "a", "c", "a"), ExactHistogram[string]().Reducer())
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(5), h.Total())
+		assert.Equal(t, uint64(3), h.Count("a"))
+		assert.Equal(t, uint64(0), h.Count("d"))
+		assert.Equal(t, []Bucket[string]{
+			{"a", 3},
+			{"b", 1},
+			{"c", 1},
+		}, h.Buckets())
+	})
+
+	t.Run("should count fixed width buckets", func(t *testing.T) {
+		h, err := Reduce(FromVals(-1, 0, 9, 10, 25), WidthHistogram(10).Reducer())
+		assert.NoError(t, err)
+
+		assert.Equal(t, []Bucket[int]{
+			{-10, 1},
+			{0, 2},
+			{10, 1},
+			{20, 1},
+		}, h.Buckets())
+	})
+
+	t.Run("should count float buckets", func(t *testing.T) {
+		h, err := Reduce(FromVals(0.1, 0.3, 0.6), WidthHistogram(0.5).Reducer())
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(2), h.Count(0.2))
+		assert.Equal(t, uint64(1), h.Count(0.9))
+	})
+
+	t.Run("should count explicit boundaries", func(t *testing.T) {
+		h, err := Reduce(FromVals(-5, 0, 1, 5, 100), BoundsHistogram(10, 0, 5).Reducer())
+		assert.NoError(t, err)
+
+		assert.Equal(t, []Bucket[int]{
+			{0, 3},
+			{5, 1},
+			{10, 1},
+		}, h.Buckets())
+	})
+
+	t.Run("should leave the original histogram empty", func(t *testing.T) {
+		h := ExactHistogram[int]()
+		_, err := Reduce(FromVals(1, 2), h.Reducer())
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(0), h.Total())
+	})
+
+	t.Run("should merge histograms", func(t *testing.T) {
+		h := WidthHistogram(10)
+		h.Add(1)
+		h.Add(15)
+
+		other := WidthHistogram(10)
+		other.Add(5)
+		other.Add(25)
+
+		h.Merge(other)
+
+		assert.Equal(t, uint64(4), h.Total())
+		assert.Equal(t, []Bucket[int]{
+			{0, 2},
+			{10, 1},
+			{20, 1},
+		}, h.Buckets())
+	})
+
+	t.Run("should reduce in parallel", func(t *testing.T) {
+		elems := make([]Elem[int], 1000)
+		for i := range elems {
+			elems[i] = Elem[int]{val: i}
+		}
+
+		hist := WidthHistogram(100)
+		h, err := ParallelReduce(fromElems(elems), 4, hist.Reducer(), hist.Combiner(), ReduceOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(1000), h.Total())
+		assert.Equal(t, uint64(100), h.Count(550))
+	})
+
+	t.Run("should handle an empty input", func(t *testing.T) {
+		h, err := Reduce(FromVals[int](), WidthHistogram(10).Reducer())
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(0), h.Total())
+		assert.Equal(t, uint64(0), h.Count(5))
+		assert.Equal(t, []Bucket[int]{}, h.Buckets())
+		assert.Equal(t, "", h.String())
+
+		_, ok := h.Percentile(50)
+		assert.False(t, ok)
+	})
+
+	t.Run("should handle an empty input in parallel", func(t *testing.T) {
+		hist := WidthHistogram(10)
+		h, err := ParallelReduce(fromElems([]Elem[int]{}), 4, hist.Reducer(), hist.Combiner(), ReduceOptions{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, uint64(0), h.Total())
+		assert.Equal(t, []Bucket[int]{}, h.Buckets())
+	})
+
+	t.Run("should find percentiles", func(t *testing.T) {
+		h, err := Reduce(makeFinite(100), ExactHistogram[int]().Reducer())
+		assert.NoError(t, err)
+
+		p, ok := h.Percentile(50)
+		assert.True(t, ok)
+		assert.Equal(t, 49, p)
+
+		p, ok = h.Percentile(100)
+		assert.True(t, ok)
+		assert.Equal(t, 99, p)
+
+		p, ok = h.Percentile(0)
+		assert.True(t, ok)
+		assert.Equal(t, 0, p)
+	})
+
+	t.Run("should not find percentiles when empty", func(t *testing.T) {
+		_, ok := ExactHistogram[int]().Percentile(50)
+		assert.False(t, ok)
+	})
+
+	t.Run("should render a summary", func(t *testing.T) {
+		h := BoundsHistogram(0, 10)
+		h.Add(1)
+		h.Add(2)
+		h.Add(11)
+
+		assert.Equal(t, " 0 | ######################################## 2\n10 | #################### 1\n", h.String())
+	})
+}
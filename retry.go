@@ -0,0 +1,179 @@
+package funky
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// DefaultRetryAttempts is the number of times Retry will call its
+// applier if the policy doesn't say otherwise.
+const DefaultRetryAttempts = 3
+
+// RetryPolicy configures Retry.
+type RetryPolicy struct {
+	// MaxAttempts is the number of times to call the applier,
+	// including the first. Zero means DefaultRetryAttempts.
+	MaxAttempts uint32
+
+	// InitialBackoff is how long to wait before the first retry.
+	// Zero means retry immediately.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the time between attempts. Zero means there
+	// is no cap.
+	MaxBackoff time.Duration
+
+	// Multiplier scales the backoff after each retry. Zero means 2,
+	// so the backoff doubles each time.
+	Multiplier float64
+
+	// Jitter is the fraction, between 0 and 1, of each backoff that
+	// is chosen at random, so that many callers that fail together
+	// don't all retry together. Zero means no jitter.
+	Jitter float64
+
+	// Retryable decides whether an error is worth retrying, errors
+	// that aren't end the attempts early. Nil means every error is
+	// retried. See RetryOn and RetryOnType.
+	Retryable func(error) bool
+
+	// Clock is used to wait between attempts. Nil means
+	// SystemClock.
+	Clock Clock
+}
+
+// Retry wraps an applier so that when it fails, it is called again,
+// with the same input, according to the policy. If every attempt
+// fails, the error wraps the errors from all of them, so errors.Is
+// and errors.As see each one.
+//
+// Example:
+//
+//	fetch := Retry(fetchPage, RetryPolicy{
+//		MaxAttempts:    5,
+//		InitialBackoff: 100 * time.Millisecond,
+//		Jitter:         0.2,
+//		Retryable:      RetryOn(ErrUnavailable),
+//	})
+func Retry[I, O any](f Applier[I, O], policy RetryPolicy) Applier[I, O] {
+	return retry(f, policy, nil)
+}
+
+// ApplyWithRetry is like Apply, but failures are retried according
+// to the policy, as with Retry. Closing the iterator cuts short any
+// wait between attempts.
+//
+// Example: pages := ApplyWithRetry(urls, fetchPage, RetryPolicy{MaxAttempts: 5})
+func ApplyWithRetry[I, O any](it *Iter[I], f Applier[I, O], policy RetryPolicy) *Iter[O] {
+	stop := make(chan interface{})
+	var stopOnce sync.Once
+
+	f = retry(f, policy, stop)
+
+	return &Iter[O]{
+		next: func() (Elem[O], bool) {
+			inElem, valid := it.Next()
+			if !valid {
+				return DoneElem[O]()
+			}
+
+			return applyElem(inElem, f)
+		},
+		close: func() {
+			stopOnce.Do(func() {
+				close(stop)
+			})
+
+			it.Close()
+		},
+	}
+}
+
+// RetryOn returns a function, for use as RetryPolicy.Retryable,
+// that retries errors that match any of the targets, according to
+// errors.Is.
+func RetryOn(targets ...error) func(error) bool {
+	return func(err error) bool {
+		for _, target := range targets {
+			if errors.Is(err, target) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// RetryOnType returns a function, for use as RetryPolicy.Retryable,
+// that retries errors that contain an error of type E, according to
+// errors.As.
+//
+// Example: Retryable: RetryOnType[*net.OpError]()
+func RetryOnType[E error]() func(error) bool {
+	return func(err error) bool {
+		var target E
+		return errors.As(err, &target)
+	}
+}
+
+// retry implements Retry, closing the stop channel cuts short any
+// wait between attempts, a nil channel never does.
+func retry[I, O any](f Applier[I, O], policy RetryPolicy, stop <-chan interface{}) Applier[I, O] {
+	attempts := policy.MaxAttempts
+	if attempts == 0 {
+		attempts = DefaultRetryAttempts
+	}
+
+	multiplier := policy.Multiplier
+	if multiplier == 0 {
+		multiplier = 2
+	}
+
+	clock := policy.Clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+
+	return func(in I) (O, error) {
+		var errs []error
+
+		backoff := policy.InitialBackoff
+		if policy.MaxBackoff > 0 {
+			backoff = min(backoff, policy.MaxBackoff)
+		}
+
+		for attempt := uint32(1); ; attempt++ {
+			out, err := f(in)
+			if err == nil {
+				return out, nil
+			}
+
+			errs = append(errs, err)
+
+			if attempt >= attempts || (policy.Retryable != nil && !policy.Retryable(err)) {
+				return out, fmt.Errorf("retry error after %d attempts: %w", attempt, errors.Join(errs...))
+			}
+
+			wait := backoff
+			if policy.Jitter > 0 {
+				wait -= time.Duration(policy.Jitter * rand.Float64() * float64(wait))
+			}
+
+			if wait > 0 {
+				select {
+				case <-clock.After(wait):
+				case <-stop:
+					return out, fmt.Errorf("retry error after %d attempts: %w", attempt, errors.Join(errs...))
+				}
+			}
+
+			backoff = time.Duration(float64(backoff) * multiplier)
+			if policy.MaxBackoff > 0 {
+				backoff = min(backoff, policy.MaxBackoff)
+			}
+		}
+	}
+}
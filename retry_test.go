@@ -0,0 +1,223 @@
+package funky
+
+import (
+	"errors"
+	"io/fs"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+// fakeClock is a Clock that only moves when something waits on it,
+// and remembers how long each wait was.
+type fakeClock struct {
+	now   time.Time
+	waits []time.Duration
+	lock  sync.Mutex
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = c.now.Add(d)
+	c.waits = append(c.waits, d)
+
+	fired := make(chan time.Time, 1)
+	fired <- c.now
+	return fired
+}
+
+// stalledClock is a Clock that never lets any time pass, it signals
+// on waiting each time something starts to wait on it.
+type stalledClock struct {
+	waiting chan interface{}
+}
+
+func (c stalledClock) Now() time.Time {
+	return time.Time{}
+}
+
+func (c stalledClock) After(d time.Duration) <-chan time.Time {
+	c.waiting <- nil
+	return nil
+}
+
+var errFlaky = errors.New("flaky")
+
+// failing returns an applier that fails the given number of times
+// before it succeeds, along with a pointer to its call count.
+func failing(failures int, err error) (Applier[int, int], *int) {
+	calls := 0
+	return func(x int) (int, error) {
+		calls++
+		if calls <= failures {
+			return 0, err
+		}
+
+		return x * 10, nil
+	}, &calls
+}
+
+func TestRetry(t *testing.T) {
+	t.Run("should not retry a success", func(t *testing.T) {
+		f, calls := failing(0, errFlaky)
+		clock := &fakeClock{}
+
+		out, err := Retry(f, RetryPolicy{Clock: clock})(1)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, out)
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, 0, len(clock.waits))
+	})
+
+	t.Run("should retry until the applier succeeds", func(t *testing.T) {
+		f, calls := failing(2, errFlaky)
+		clock := &fakeClock{}
+
+		out, err := Retry(f, RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: time.Second,
+			Clock:          clock,
+		})(1)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, out)
+		assert.Equal(t, 3, *calls)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, clock.waits)
+	})
+
+	t.Run("should wrap every error once attempts run out", func(t *testing.T) {
+		first := errors.New("first")
+		calls := 0
+		f := func(x int) (int, error) {
+			calls++
+			if calls == 1 {
+				return 0, first
+			}
+
+			return 0, errFlaky
+		}
+
+		_, err := Retry(f, RetryPolicy{Clock: &fakeClock{}})(1)
+		assert.Equal(t, DefaultRetryAttempts, calls)
+		assert.IsError(t, err, first)
+		assert.IsError(t, err, errFlaky)
+		assert.EqualError(t, err, "retry error after 3 attempts: first\nflaky\nflaky")
+	})
+
+	t.Run("should grow the backoff up to the cap", func(t *testing.T) {
+		f, _ := failing(10, errFlaky)
+		clock := &fakeClock{}
+
+		_, err := Retry(f, RetryPolicy{
+			MaxAttempts:    5,
+			InitialBackoff: time.Second,
+			MaxBackoff:     5 * time.Second,
+			Multiplier:     3,
+			Clock:          clock,
+		})(1)
+		assert.Error(t, err)
+		assert.Equal(t, []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second}, clock.waits)
+	})
+
+	t.Run("should shorten waits with jitter", func(t *testing.T) {
+		f, _ := failing(100, errFlaky)
+		clock := &fakeClock{}
+
+		_, err := Retry(f, RetryPolicy{
+			MaxAttempts:    20,
+			InitialBackoff: time.Second,
+			Multiplier:     1,
+			Jitter:         0.5,
+			Clock:          clock,
+		})(1)
+		assert.Error(t, err)
+
+		for _, wait := range clock.waits {
+			assert.True(t, wait >= time.Second/2 && wait <= time.Second)
+		}
+	})
+
+	t.Run("should stop at errors that are not retryable", func(t *testing.T) {
+		f, calls := failing(10, fs.ErrNotExist)
+
+		_, err := Retry(f, RetryPolicy{
+			Retryable: RetryOn(fs.ErrPermission, errFlaky),
+			Clock:     &fakeClock{},
+		})(1)
+		assert.IsError(t, err, fs.ErrNotExist)
+		assert.Equal(t, 1, *calls)
+	})
+
+	t.Run("should retry errors by type", func(t *testing.T) {
+		f, calls := failing(1, &fs.PathError{Op: "open", Path: "x", Err: fs.ErrNotExist})
+
+		out, err := Retry(f, RetryPolicy{
+			Retryable: RetryOnType[*fs.PathError](),
+			Clock:     &fakeClock{},
+		})(1)
+		assert.NoError(t, err)
+		assert.Equal(t, 10, out)
+		assert.Equal(t, 2, *calls)
+
+		f, calls = failing(1, errFlaky)
+		_, err = Retry(f, RetryPolicy{
+			Retryable: RetryOnType[*fs.PathError](),
+			Clock:     &fakeClock{},
+		})(1)
+		assert.IsError(t, err, errFlaky)
+		assert.Equal(t, 1, *calls)
+	})
+}
+
+func TestApplyWithRetry(t *testing.T) {
+	t.Run("should retry each value", func(t *testing.T) {
+		f, calls := failing(1, errFlaky)
+		retried := ApplyWithRetry(FromVals(1, 2), f, RetryPolicy{Clock: &fakeClock{}})
+
+		assertValues(t, retried, []int{10, 20}, true)
+		assert.Equal(t, 3, *calls)
+	})
+
+	t.Run("should pass through input errors", func(t *testing.T) {
+		f, calls := failing(0, errFlaky)
+		retried := ApplyWithRetry(makeErroneous(), f, RetryPolicy{Clock: &fakeClock{}})
+
+		elem, valid := retried.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+		assert.Equal(t, 0, *calls)
+	})
+
+	t.Run("should stop waiting when closed", func(t *testing.T) {
+		f, _ := failing(10, errFlaky)
+		source := makeInfinite()
+		clock := stalledClock{waiting: make(chan interface{})}
+		retried := ApplyWithRetry(source, f, RetryPolicy{
+			InitialBackoff: time.Hour,
+			Clock:          clock,
+		})
+
+		result := make(chan Elem[int])
+		go func() {
+			elem, _ := retried.Next()
+			result <- elem
+		}()
+
+		<-clock.waiting
+		go retried.Close()
+
+		elem := <-result
+		assert.IsError(t, elem.err, errFlaky)
+		assertClosed(t, source)
+	})
+}
@@ -0,0 +1,153 @@
+package funky
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNoRoute is reported by Route for values whose key doesn't
+// match any of the outputs.
+var ErrNoRoute = errors.New("no route")
+
+// Partition splits the iterator into two, the first produces the
+// values that match the predicate and the second produces the rest.
+// Errors go to the first, just as Where would keep them. The
+// predicate is only called once for each value.
+//
+// Values are held in memory until their output takes them, so if
+// one output is read much further than the other, memory use grows
+// without bound. The original is closed once both outputs have been
+// closed, and values meant for a closed output are discarded.
+//
+// For example (in pseudocode):
+//
+//	Partition({1, 2, 3, 4}, x -> x%2 == 0) -> {2, 4}, {1, 3}
+func Partition[T any](it *Iter[T], pred Predicate[T]) (matching, rest *Iter[T]) {
+	outputs := newRouter(it, 2, func(elem Elem[T]) (Elem[T], int) {
+		if elem.err != nil || pred(elem.val) {
+			return elem, 0
+		}
+
+		return elem, 1
+	}).outputs()
+
+	return outputs[0], outputs[1]
+}
+
+// Route splits the iterator into one iterator for each of the given
+// keys, each value goes to the output for its key. Errors go to a
+// separate output, along with any values whose key doesn't match
+// one of the outputs, which arrive wrapped in an error that matches
+// ErrNoRoute, so they can still be recovered with Value.
+//
+// Like Partition, values are held in memory until their output
+// takes them, and the original is closed once every output,
+// including the error output, has been closed.
+//
+// Example:
+//
+//	tenants, errs := Route(events, func(e Event) string { return e.Tenant }, "acme", "globex")
+//	go WriteJSONLines(tenants["acme"], acmeFile, WriteOptions[Event]{})
+func Route[T any, K comparable](it *Iter[T], key KeyFunc[T, K], keys ...K) (map[K]*Iter[T], *Iter[T]) {
+	indexes := map[K]int{}
+	for _, k := range keys {
+		if _, ok := indexes[k]; !ok {
+			indexes[k] = len(indexes)
+		}
+	}
+
+	// The error output comes last.
+	errIndex := len(indexes)
+
+	outputs := newRouter(it, errIndex+1, func(elem Elem[T]) (Elem[T], int) {
+		if elem.err != nil {
+			return elem, errIndex
+		}
+
+		k := key(elem.val)
+		i, ok := indexes[k]
+		if !ok {
+			elem.err = fmt.Errorf("%w for key %v", ErrNoRoute, k)
+			return elem, errIndex
+		}
+
+		return elem, i
+	}).outputs()
+
+	routes := map[K]*Iter[T]{}
+	for k, i := range indexes {
+		routes[k] = outputs[i]
+	}
+
+	return routes, outputs[errIndex]
+}
+
+// router hands out the elements from a single source to several
+// consumers, each element goes to exactly one of them, as decided
+// by route, which may also change the element on its way. Elements
+// are pulled from the source by whichever consumer runs out first,
+// and queued until their consumer takes them.
+type router[T any] struct {
+	splitter[T]
+
+	route  func(Elem[T]) (Elem[T], int)
+	queues [][]Elem[T]
+}
+
+func newRouter[T any](it *Iter[T], n int, route func(Elem[T]) (Elem[T], int)) *router[T] {
+	r := &router[T]{
+		route:  route,
+		queues: make([][]Elem[T], n),
+	}
+	r.init(it, n)
+
+	return r
+}
+
+func (r *router[T]) outputs() []*Iter[T] {
+	// Nobody is going to take what was queued for a closed
+	// consumer, so it can go.
+	return r.splitter.outputs(r.next, func(i int) {
+		r.queues[i] = nil
+	})
+}
+
+func (r *router[T]) next(i int) (Elem[T], bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for {
+		if r.closed[i] {
+			return DoneElem[T]()
+		}
+
+		if len(r.queues[i]) > 0 {
+			elem := r.queues[i][0]
+			r.queues[i][0] = Elem[T]{}
+			r.queues[i] = r.queues[i][1:]
+			return elem, true
+		}
+
+		if r.exhausted {
+			return DoneElem[T]()
+		}
+
+		if r.pulling {
+			r.cond.Wait()
+			continue
+		}
+
+		elem, valid := r.pull()
+		if !valid {
+			r.exhausted = true
+			continue
+		}
+
+		// Routing happens with the lock held, which is fine for
+		// the predicates and key functions it calls.
+		elem, k := r.route(elem)
+		if !r.closed[k] {
+			r.queues[k] = append(r.queues[k], elem)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package funky
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func even(x int) bool {
+	return x%2 == 0
+}
+
+func TestPartition(t *testing.T) {
+	t.Run("should split values by the predicate", func(t *testing.T) {
+		matching, rest := Partition(FromVals(1, 2, 3, 4), even)
+
+		assertValues(t, rest, []int{1, 3}, true)
+		assertValues(t, matching, []int{2, 4}, true)
+	})
+
+	t.Run("should send errors to the matching output", func(t *testing.T) {
+		matching, rest := Partition(makeMixed(), even)
+
+		assertValues(t, rest, []int{1}, true)
+		elem, valid := matching.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+		assertValues(t, matching, []int{2}, true)
+	})
+
+	t.Run("should call the predicate once per value", func(t *testing.T) {
+		calls := 0
+		matching, rest := Partition(FromVals(1, 2, 3, 4), func(x int) bool {
+			calls++
+			return even(x)
+		})
+
+		assert.Equal(t, 2, len(matching.ToSlice(10)))
+		assert.Equal(t, 2, len(rest.ToSlice(10)))
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("should allow concurrent outputs", func(t *testing.T) {
+		matching, rest := Partition(makeFinite(1000), even)
+
+		var wg sync.WaitGroup
+		for _, output := range []*Iter[int]{matching, rest} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Equal(t, 500, len(output.ToSlice(1000)))
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("should close the source once both outputs are closed", func(t *testing.T) {
+		source := makeInfinite()
+		matching, rest := Partition(source, even)
+
+		assertValues(t, matching, []int{0, 2}, false)
+		matching.Close()
+
+		select {
+		case <-source.Done():
+			t.Fatal("source closed too early")
+		default:
+		}
+
+		assertValues(t, rest, []int{1, 3, 5}, false)
+		rest.Close()
+		assertClosed(t, source)
+	})
+}
+
+func TestRoute(t *testing.T) {
+	t.Run("should split values by key", func(t *testing.T) {
+		routes, errs := Route(FromVals("a", "bb", "cc", "d"), byLength, 1, 2)
+		assert.Equal(t, 2, len(routes))
+
+		assertValues(t, routes[2], []string{"bb", "cc"}, true)
+		assertValues(t, routes[1], []string{"a", "d"}, true)
+		assertValues(t, errs, []string{}, true)
+	})
+
+	t.Run("should send errors to the error output", func(t *testing.T) {
+		routes, errs := Route(makeMixed(), even, true, false)
+
+		assertValues(t, routes[false], []int{1}, true)
+		assertValues(t, routes[true], []int{2}, true)
+
+		elem, valid := errs.Next()
+		assert.True(t, valid)
+		assert.EqualError(t, elem.err, "error")
+		assertValues(t, errs, []int{}, true)
+	})
+
+	t.Run("should send unknown keys to the error output", func(t *testing.T) {
+		routes, errs := Route(FromVals("a", "bbb"), byLength, 1)
+
+		assertValues(t, routes[1], []string{"a"}, true)
+
+		elem, valid := errs.Next()
+		assert.True(t, valid)
+		assert.IsError(t, elem.Err(), ErrNoRoute)
+		assert.EqualError(t, elem.Err(), "no route for key 3")
+		assert.Equal(t, "bbb", elem.Value())
+	})
+
+	t.Run("should close the source once every output is closed", func(t *testing.T) {
+		source := makeInfinite()
+		routes, errs := Route(source, even, true, false)
+
+		routes[true].Close()
+		routes[false].Close()
+
+		select {
+		case <-source.Done():
+			t.Fatal("source closed too early")
+		default:
+		}
+
+		errs.Close()
+		assertClosed(t, source)
+	})
+}
@@ -0,0 +1,72 @@
+package funky
+
+import "sync"
+
+// Scan is like Reduce, but instead of only producing the final
+// accumulator, it produces the accumulator after each value has
+// been incorporated into it, starting from init.
+//
+// Updates are serialized, and each value is pulled from the source
+// and incorporated in a single step, so the running values always
+// reflect the order in which the source produced its values, even
+// if Next is called from several goroutines.
+//
+// Input errors are passed along and don't affect the accumulator.
+// If the reducer returns an error, the error is produced instead
+// and the accumulator is left as it was.
+//
+// For example (in pseudocode):
+//
+//	Scan({1, 2, 3}, 10, (sum, x) -> sum + x) -> {11, 13, 16}
+func Scan[I, A any](it *Iter[I], init A, f Reducer[I, A]) *Iter[A] {
+	step := accumulate(init, f)
+	var lock sync.Mutex
+
+	return &Iter[A]{
+		next: func() (Elem[A], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			elem, valid := it.Next()
+			if !valid {
+				return DoneElem[A]()
+			}
+
+			if elem.err != nil {
+				return ErrElem[A](elem.err)
+			}
+
+			acc, err := step(elem.val)
+			if err != nil {
+				return ErrElem[A](err)
+			}
+
+			return ValElem(acc)
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// accumulate creates an applier that incorporates each value it is
+// given into an accumulator, starting from init, and produces the
+// updated accumulator. Updates are serialized, so the applier can
+// be used with Apply from several goroutines.
+func accumulate[I, A any](init A, f Reducer[I, A]) Applier[I, A] {
+	acc := init
+	var lock sync.Mutex
+
+	return func(v I) (A, error) {
+		lock.Lock()
+		defer lock.Unlock()
+
+		next, err := f(acc, v)
+		if err != nil {
+			return acc, err
+		}
+
+		acc = next
+		return acc, nil
+	}
+}
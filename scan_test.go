@@ -0,0 +1,60 @@
+package funky
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestScan(t *testing.T) {
+	add := func(sum, v int) (int, error) {
+		return sum + v, nil
+	}
+
+	t.Run("should produce running values from the initial value", func(t *testing.T) {
+		it := Scan(FromVals(1, 2, 3), 10, add)
+		assertValues(t, it, []int{11, 13, 16}, true)
+	})
+
+	t.Run("should change the accumulator type", func(t *testing.T) {
+		it := Scan(FromVals("a", "b", "c"), []string{}, func(acc []string, v string) ([]string, error) {
+			return append(acc, v), nil
+		})
+		assertValues(t, it, [][]string{{"a"}, {"a", "b"}, {"a", "b", "c"}}, true)
+	})
+
+	t.Run("should pass along input errors", func(t *testing.T) {
+		it := Scan(makeMixed(), 0, add)
+		assertValues(t, it, []int{1}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []int{3}, true)
+	})
+
+	t.Run("should skip values the reducer rejects", func(t *testing.T) {
+		it := Scan(FromVals(1, -1, 2), 0, func(sum, v int) (int, error) {
+			if v < 0 {
+				return 0, errors.New("negative")
+			}
+
+			return sum + v, nil
+		})
+		assertValues(t, it, []int{1}, false)
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []int{3}, true)
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		src := makeFinite(3)
+		Scan(src, 0, add).Close()
+		assertClosed(t, src)
+	})
+}
@@ -12,8 +12,19 @@ type Pair[L any, R any] struct {
 	Right R
 }
 
+// An Option is a value that may be missing, such as the side of
+// an outer join that had nothing to match with.
+type Option[T any] struct {
+	Value T
+	Valid bool
+}
+
 type Predicate[T any] func(T) bool
 
+// A KeyFunc extracts a key from a value so that values can be
+// matched up with one another, such as when grouping.
+type KeyFunc[T, K any] func(T) K
+
 // Chunk returns an iterator that produces several values from
 // the original iterator as slices of values ("chunks").
 func Chunk[T any](iter *Iter[T], size uint64) *Iter[[]T] {
@@ -49,13 +60,67 @@ func Chunk[T any](iter *Iter[T], size uint64) *Iter[[]T] {
 
 		},
 		close: func() {
-			size = 0
+			iter.Close()
+		},
+	}
+}
+
+// Flatten reverses the Chunk operation, producing each value from
+// each slice, in order. Slices may be of any length, so the final
+// chunk being shorter than the rest is no problem.
+//
+// If a slice carries an error, such as the joined errors from
+// Chunk, the error is passed along with each of its values. An
+// empty slice that carries an error produces a single element
+// with just the error, so that it isn't lost.
+//
+// For example (in pseudocode):
+//
+//	Flatten({{1, 2}, {3}}) -> {1, 2, 3}
+func Flatten[T any](it *Iter[[]T]) *Iter[T] {
+	var current []T
+	var currentErr error
+	var lock sync.Mutex
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			for len(current) == 0 {
+				elem, valid := it.Next()
+				if !valid {
+					return DoneElem[T]()
+				}
+
+				if len(elem.val) == 0 {
+					if elem.err != nil {
+						return ErrElem[T](elem.err)
+					}
+
+					continue
+				}
+
+				current = elem.val
+				currentErr = elem.err
+			}
+
+			val := current[0]
+			current = current[1:]
+
+			return Elem[T]{
+				val: val,
+				err: currentErr,
+			}, true
+		},
+		close: func() {
+			it.Close()
 		},
 	}
 }
 
 // NoError simply skips any elements that include an
-// error value.
+// error value. It is the same as HandleErrors with SkipErrors.
 func NoError[T any](it *Iter[T]) *Iter[T] {
 	return &Iter[T]{
 		next: func() (Elem[T], bool) {
@@ -71,7 +136,7 @@ func NoError[T any](it *Iter[T]) *Iter[T] {
 			}
 		},
 		close: func() {
-			it = nil
+			it.Close()
 		},
 	}
 }
@@ -97,7 +162,7 @@ func Take[T any](it *Iter[T], n uint64) *Iter[T] {
 			return it.Next()
 		},
 		close: func() {
-			it = nil
+			it.Close()
 		},
 	}
 }
@@ -121,7 +186,7 @@ func Where[T any](it *Iter[T], keep Predicate[T]) *Iter[T] {
 			}
 		},
 		close: func() {
-			it = nil
+			it.Close()
 		},
 	}
 }
@@ -188,6 +253,9 @@ func While[T any](it *Iter[T], still Predicate[T]) *Iter[T] {
 
 			return DoneElem[T]()
 		},
+		close: func() {
+			it.Close()
+		},
 	}
 }
 
@@ -210,6 +278,10 @@ func While[T any](it *Iter[T], still Predicate[T]) *Iter[T] {
 // drain the non-empty iterator, but the zipped iterator will
 // still signal that it is empty.
 //
+// If either element carries an error, the pair carries both errors,
+// joined together, along with both values, so a good value isn't
+// lost because its partner is bad.
+//
 // Note that accesses to the left and right iterators are not
 // synchronized, so if another goroutine is using one or both
 // of them, the values produced by Zip may be out of order.
@@ -223,16 +295,14 @@ func Zip[L, R any](left *Iter[L], right *Iter[R]) *Iter[Pair[L, R]] {
 				return DoneElem[Pair[L, R]]()
 			}
 
-			err := errors.Join(leftElem.err, rightElem.err)
-			if err != nil {
-				return ErrElem[Pair[L, R]](err)
-			}
-
-			return ValElem(Pair[L, R]{leftElem.val, rightElem.val})
+			return ResultElem(
+				Pair[L, R]{leftElem.val, rightElem.val},
+				errors.Join(leftElem.err, rightElem.err),
+			)
 		},
 		close: func() {
-			left = nil
-			right = nil
+			left.Close()
+			right.Close()
 		},
 	}
 }
@@ -24,6 +24,47 @@ func TestChunk(t *testing.T) {
 		assert.True(t, valid)
 		assert.Equal(t, []int{3}, second.val)
 	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		iter := makeFinite(4)
+		Chunk(iter, 2).Close()
+		assertClosed(t, iter)
+	})
+}
+
+func TestFlatten(t *testing.T) {
+	t.Run("should reverse Chunk", func(t *testing.T) {
+		it := Flatten(Chunk(makeFinite(5), 2))
+		assertValues(t, it, []int{0, 1, 2, 3, 4}, true)
+	})
+
+	t.Run("should skip empty slices", func(t *testing.T) {
+		it := Flatten(FromVals([]int{}, []int{1}, nil, []int{2, 3}))
+		assertValues(t, it, []int{1, 2, 3}, true)
+	})
+
+	t.Run("should attach a chunk error to its values", func(t *testing.T) {
+		it := Flatten(Chunk(makeMixed(), 2))
+
+		for _, want := range []int{1, 0} {
+			elem, valid := it.Next()
+			assert.True(t, valid)
+			assert.Equal(t, want, elem.val)
+			assert.Error(t, elem.err)
+		}
+
+		assertValues(t, it, []int{2}, true)
+	})
+
+	t.Run("should keep the error from an empty slice", func(t *testing.T) {
+		it := Flatten(fromElems([]Elem[[]int]{
+			{err: errors.New("error")},
+		}))
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+	})
 }
 
 func TestNoError(t *testing.T) {
@@ -68,7 +109,12 @@ func TestNoError(t *testing.T) {
 	})
 
 	t.Run("should close correctly", func(t *testing.T) {
-		//
+		src := makeFinite(2)
+		iter := NoError(src)
+		iter.Close()
+
+		assertValues(t, iter, []int{}, true)
+		assertClosed(t, src)
 	})
 }
 
@@ -99,7 +145,14 @@ func TestTake(t *testing.T) {
 	})
 
 	t.Run("should not take after close", func(t *testing.T) {
-		//
+		src := makeFinite(3)
+		iter := Take(src, 2)
+		assertValues(t, iter, []int{0}, false)
+
+		iter.Close()
+
+		assertValues(t, iter, []int{}, true)
+		assertClosed(t, src)
 	})
 }
 
@@ -132,7 +185,14 @@ func TestWhere(t *testing.T) {
 	})
 
 	t.Run("should close correctly", func(t *testing.T) {
-		//
+		src := makeFinite(4)
+		iter := Where(src, func(v int) bool {
+			return true
+		})
+		iter.Close()
+
+		assertValues(t, iter, []int{}, true)
+		assertClosed(t, src)
 	})
 }
 
@@ -154,6 +214,14 @@ func TestWhile(t *testing.T) {
 		})
 		assertValues(t, iter, []int{}, true)
 	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		src := makeFinite(3)
+		While(src, func(v int) bool {
+			return true
+		}).Close()
+		assertClosed(t, src)
+	})
 }
 
 func TestZip(t *testing.T) {
@@ -188,4 +256,26 @@ func TestZip(t *testing.T) {
 		iter := Zip(left, right)
 		assertValues(t, iter, []Pair[int, int]{}, true)
 	})
+
+	t.Run("should keep values alongside errors", func(t *testing.T) {
+		iter := Zip(makeMixed(), FromVals("a", "b", "c"))
+
+		assertValues(t, iter, []Pair[int, string]{{1, "a"}}, false)
+
+		elem, valid := iter.Next()
+		assert.True(t, valid)
+		assert.EqualError(t, elem.Err(), "error")
+		assert.Equal(t, "b", elem.Value().Right)
+
+		assertValues(t, iter, []Pair[int, string]{{2, "c"}}, true)
+	})
+
+	t.Run("should close both sources", func(t *testing.T) {
+		left := makeFinite(2)
+		right := makeFinite(2)
+
+		Zip(left, right).Close()
+		assertClosed(t, left)
+		assertClosed(t, right)
+	})
 }
@@ -0,0 +1,223 @@
+package funky
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// DefaultSortMemory is the number of values SortBy will hold in
+// memory at once if the options don't say otherwise.
+const DefaultSortMemory = 1 << 20
+
+// SortOptions configures SortBy.
+type SortOptions[T any] struct {
+	// MaxInMemory is the number of values to sort in memory before
+	// spilling them, as a sorted run, to a temporary file. Zero means
+	// DefaultSortMemory.
+	MaxInMemory uint64
+
+	// Codec is used to write spilled values to disk and read them
+	// back again. Zero means GobCodec.
+	Codec Codec[T]
+
+	// TempDir is the directory where runs are spilled. Empty means
+	// the default directory for temporary files.
+	TempDir string
+}
+
+// SortBy produces the values from the iterator in the order given
+// by compare, which should return a negative number when a < b, a
+// positive number when a > b, and zero otherwise, like cmp.Compare.
+// The sort is stable, so equal values keep their original order.
+//
+// Since sorting can't begin until the input has been exhausted,
+// the entire input is consumed the first time Next is called. Once
+// the options' memory limit is reached, values are sorted and
+// spilled to a temporary file, and the sorted files are merged as
+// values are requested. The files are removed once the iterator is
+// exhausted or closed.
+//
+// Errors can't be sorted, so they are passed along, in order, ahead
+// of the values, as are any errors from spilling to disk. If the
+// comparison, or the input, panics along the way, the values
+// gathered so far are lost, so the iterator ends.
+//
+// Example: byAge := SortBy(people, func(a, b Person) int { return a.Age - b.Age }, SortOptions[Person]{})
+func SortBy[T any](it *Iter[T], compare func(a, b T) int, opts SortOptions[T]) *Iter[T] {
+	var sorted *Iter[T]
+	var lock sync.Mutex
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			// Start with an empty result so that, if sorting
+			// panics, we end rather than sort whatever is left.
+			if sorted == nil {
+				sorted = FromSlice[T](nil)
+				sorted = sortAll(it, compare, opts)
+			}
+
+			return sorted.Next()
+		},
+		close: func() {
+			// Closing the source first cuts short any sort that
+			// is in progress, so we don't wait long for the lock.
+			it.Close()
+
+			lock.Lock()
+			defer lock.Unlock()
+
+			if sorted != nil {
+				sorted.Close()
+			}
+		},
+	}
+}
+
+// sortAll drains the iterator and returns a new iterator that will
+// produce its errors, followed by its values in sorted order.
+func sortAll[T any](it *Iter[T], compare func(a, b T) int, opts SortOptions[T]) *Iter[T] {
+	limit := opts.MaxInMemory
+	if limit == 0 {
+		limit = DefaultSortMemory
+	}
+
+	codec := opts.Codec
+	if codec == nil {
+		codec = GobCodec[T]{}
+	}
+
+	var errs []Elem[T]
+	var vals []T
+	var runs []*Iter[T]
+
+	// Spilled runs are removed if we don't make it to the end,
+	// which can only happen if something panics.
+	finished := false
+	defer func() {
+		if !finished {
+			for _, run := range runs {
+				run.Close()
+			}
+		}
+	}()
+
+	for elem, valid := it.Next(); valid; elem, valid = it.Next() {
+		if elem.err != nil {
+			errs = append(errs, elem)
+			continue
+		}
+
+		vals = append(vals, elem.val)
+		if uint64(len(vals)) < limit {
+			continue
+		}
+
+		slices.SortStableFunc(vals, compare)
+
+		path, err := writeRun(vals, codec, opts.TempDir)
+		if err != nil {
+			for _, run := range runs {
+				run.Close()
+			}
+
+			finished = true
+
+			errs = append(errs, Elem[T]{err: fmt.Errorf("sort spill error: %w", err)})
+			return fromElems(errs)
+		}
+
+		runs = append(runs, readRun(path, codec))
+		vals = vals[:0]
+	}
+
+	slices.SortStableFunc(vals, compare)
+	finished = true
+
+	if len(runs) == 0 {
+		return Concat(fromElems(errs), FromSlice(vals))
+	}
+
+	// The values still in memory make up the final run, they must
+	// come last so that the merge remains stable.
+	runs = append(runs, FromSlice(vals))
+
+	return Concat(fromElems(errs), MergeSorted(compare, runs...))
+}
+
+// writeRun writes the values to a new temporary file and returns
+// its path.
+func writeRun[T any](vals []T, codec Codec[T], dir string) (string, error) {
+	f, err := os.CreateTemp(dir, "funky-sort-*")
+	if err != nil {
+		return "", err
+	}
+
+	w := bufio.NewWriter(f)
+	encode := codec.Encoder(w)
+	for _, v := range vals {
+		if err = encode(v); err != nil {
+			break
+		}
+	}
+
+	if err == nil {
+		err = w.Flush()
+	}
+
+	err = errors.Join(err, f.Close())
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// readRun creates an iterator that reads back the values written
+// by writeRun. The file is removed once the iterator is exhausted
+// or closed.
+func readRun[T any](path string, codec Codec[T]) *Iter[T] {
+	f, err := os.Open(path)
+	if err != nil {
+		_ = os.Remove(path)
+		return fromElems([]Elem[T]{{err: fmt.Errorf("sort read error: %w", err)}})
+	}
+
+	decode := codec.Decoder(bufio.NewReader(f))
+	done := false
+	cleanup := func() {
+		done = true
+		_ = f.Close()
+		_ = os.Remove(path)
+	}
+
+	return &Iter[T]{
+		next: func() (Elem[T], bool) {
+			if done {
+				return DoneElem[T]()
+			}
+
+			v, err := decode()
+			if errors.Is(err, io.EOF) {
+				cleanup()
+				return DoneElem[T]()
+			}
+
+			if err != nil {
+				cleanup()
+				return ErrElem[T](fmt.Errorf("sort read error: %w", err))
+			}
+
+			return ValElem(v)
+		},
+		close: cleanup,
+	}
+}
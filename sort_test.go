@@ -0,0 +1,101 @@
+package funky
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestSortBy(t *testing.T) {
+	shuffled := func(n int) []int {
+		vals := make([]int, n)
+		for i := range vals {
+			vals[i] = i
+		}
+
+		rand.Shuffle(len(vals), func(i, j int) {
+			vals[i], vals[j] = vals[j], vals[i]
+		})
+
+		return vals
+	}
+
+	t.Run("should sort in memory", func(t *testing.T) {
+		it := SortBy(FromSlice(shuffled(10)), cmp.Compare[int], SortOptions[int]{})
+		assertValues(t, it, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, true)
+	})
+
+	t.Run("should handle an empty iterator", func(t *testing.T) {
+		it := SortBy(makeFinite(0), cmp.Compare[int], SortOptions[int]{})
+		assertValues(t, it, []int{}, true)
+	})
+
+	t.Run("should spill to disk and merge", func(t *testing.T) {
+		for name, codec := range map[string]Codec[int]{
+			"gob":  GobCodec[int]{},
+			"json": JSONCodec[int]{},
+		} {
+			t.Run(name, func(t *testing.T) {
+				dir := t.TempDir()
+				it := SortBy(FromSlice(shuffled(100)), cmp.Compare[int], SortOptions[int]{
+					MaxInMemory: 7,
+					Codec:       codec,
+					TempDir:     dir,
+				})
+
+				values := it.ToSlice(1000)
+				assert.True(t, slices.IsSorted(values))
+				assert.Equal(t, 100, len(values))
+
+				entries, err := os.ReadDir(dir)
+				assert.NoError(t, err)
+				assert.Equal(t, 0, len(entries))
+			})
+		}
+	})
+
+	t.Run("should be stable", func(t *testing.T) {
+		pairs := []Pair[int, int]{{1, 0}, {0, 1}, {1, 2}, {0, 3}, {1, 4}, {0, 5}}
+		it := SortBy(FromSlice(pairs), func(a, b Pair[int, int]) int {
+			return cmp.Compare(a.Left, b.Left)
+		}, SortOptions[Pair[int, int]]{
+			MaxInMemory: 2,
+			TempDir:     t.TempDir(),
+		})
+
+		assertValues(t, it, []Pair[int, int]{{0, 1}, {0, 3}, {0, 5}, {1, 0}, {1, 2}, {1, 4}}, true)
+	})
+
+	t.Run("should pass errors ahead of values", func(t *testing.T) {
+		it := SortBy(makeMixed(), func(a, b int) int {
+			return cmp.Compare(b, a)
+		}, SortOptions[int]{})
+
+		elem, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+
+		assertValues(t, it, []int{2, 1}, true)
+	})
+
+	t.Run("should remove spilled runs when closed", func(t *testing.T) {
+		dir := t.TempDir()
+		src := FromSlice(shuffled(20))
+		it := SortBy(src, cmp.Compare[int], SortOptions[int]{
+			MaxInMemory: 5,
+			TempDir:     dir,
+		})
+		assertValues(t, it, []int{0}, false)
+
+		it.Close()
+		assertClosed(t, src)
+
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(entries))
+	})
+}
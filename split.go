@@ -0,0 +1,93 @@
+package funky
+
+import "sync"
+
+// splitter holds what fanout and router have in common: several
+// consumers that share a single source, pulling from it whenever
+// one of them runs out. The types that embed it decide what each
+// consumer gets, using the lock and condition to coordinate.
+type splitter[T any] struct {
+	source *Iter[T]
+
+	closed []bool
+	open   int
+
+	exhausted bool
+
+	// pulling is set while a consumer waits on the source, which
+	// happens outside the lock so that the others can be closed.
+	pulling bool
+
+	lock sync.Mutex
+	cond *sync.Cond
+}
+
+// init prepares the splitter for n consumers. The condition refers
+// to the lock, so the splitter must already be in its final place.
+func (s *splitter[T]) init(it *Iter[T], n int) {
+	s.source = it
+	s.closed = make([]bool, n)
+	s.open = n
+	s.cond = sync.NewCond(&s.lock)
+}
+
+// outputs creates an iterator for each consumer. Next produces the
+// i-th consumer's next element, and release lets go of anything
+// held for it once it has been closed. Release is called with the
+// lock held.
+func (s *splitter[T]) outputs(next func(i int) (Elem[T], bool), release func(i int)) []*Iter[T] {
+	outputs := make([]*Iter[T], len(s.closed))
+	for i := range outputs {
+		outputs[i] = &Iter[T]{
+			next: func() (Elem[T], bool) {
+				return next(i)
+			},
+			close: func() {
+				s.close(i, release)
+			},
+		}
+	}
+
+	return outputs
+}
+
+// pull waits on the source with the lock released, so that the
+// other consumers can be closed in the meantime. It must be called
+// with the lock held, and returns with it held, even if the source
+// panics.
+func (s *splitter[T]) pull() (Elem[T], bool) {
+	s.pulling = true
+	s.lock.Unlock()
+
+	defer func() {
+		s.lock.Lock()
+		s.pulling = false
+		s.cond.Broadcast()
+	}()
+
+	return s.source.Next()
+}
+
+// close marks the i-th consumer as closed, releases whatever was
+// held for it, and wakes up the others, since they may be able to
+// move on now. The source is closed along with the last consumer.
+func (s *splitter[T]) close(i int, release func(i int)) {
+	s.lock.Lock()
+
+	if s.closed[i] {
+		s.lock.Unlock()
+		return
+	}
+
+	s.closed[i] = true
+	s.open--
+	last := s.open == 0
+
+	release(i)
+	s.cond.Broadcast()
+	s.lock.Unlock()
+
+	if last {
+		s.source.Close()
+	}
+}
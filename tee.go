@@ -0,0 +1,337 @@
+package funky
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// LagPolicy decides what Broadcast does when its buffer is full
+// because one of its outputs has fallen behind the others.
+type LagPolicy int
+
+const (
+	// BlockOnLag makes the outputs that are ahead wait for the
+	// ones that are behind to catch up. Nothing is lost, but the
+	// outputs must be read concurrently, or the pipeline will stall.
+	BlockOnLag LagPolicy = iota
+
+	// DropOnLag discards the oldest buffered value to make room for
+	// a new one, so the outputs that are behind miss it.
+	DropOnLag
+
+	// SpillOnLag moves the oldest buffered values to a temporary
+	// file to make room for new ones. Nothing is lost and nobody
+	// waits, at the cost of disk space.
+	SpillOnLag
+)
+
+// DefaultBroadcastSize is the number of values Broadcast will hold
+// in memory at once if the options don't say otherwise.
+const DefaultBroadcastSize = 1024
+
+// BroadcastOptions configures Broadcast.
+type BroadcastOptions[T any] struct {
+	// Size is the number of values held in memory for outputs that
+	// have fallen behind. Zero means DefaultBroadcastSize.
+	Size uint64
+
+	// OnLag decides what happens once Size values are held.
+	OnLag LagPolicy
+
+	// Codec is used to write spilled values to disk and read them
+	// back again. Zero means GobCodec.
+	Codec Codec[T]
+
+	// TempDir is the directory where values are spilled. Empty
+	// means the default directory for temporary files.
+	TempDir string
+}
+
+// Tee splits the iterator into n iterators that each produce every
+// value, and error, from the original. Values are held in memory
+// until every output has taken them, so if one output is read much
+// further than another, memory use grows without bound. Use
+// Broadcast to put a limit on it.
+//
+// The original is closed once every output has been closed.
+//
+// Example:
+//
+//	outputs := Tee(records, 2)
+//	go WriteJSONLines(outputs[0], file, WriteOptions[Record]{})
+//	stats, err := Reduce(outputs[1], summarize)
+func Tee[T any](it *Iter[T], n uint32) []*Iter[T] {
+	return newFanout(it, n, 0, BroadcastOptions[T]{}).outputs()
+}
+
+// Broadcast is like Tee, but it holds at most the number of values
+// given in the options in memory. Once that many are held, the
+// options' lag policy decides whether to wait, to drop values, or
+// to spill them to a temporary file. Problems with the spill file
+// are reported as errors and, if spilling fails, values are held in
+// memory instead.
+//
+// Example: outputs := Broadcast(records, 2, BroadcastOptions[Record]{OnLag: SpillOnLag})
+func Broadcast[T any](it *Iter[T], n uint32, opts BroadcastOptions[T]) []*Iter[T] {
+	size := opts.Size
+	if size == 0 {
+		size = DefaultBroadcastSize
+	}
+
+	if opts.Codec == nil {
+		opts.Codec = GobCodec[T]{}
+	}
+
+	return newFanout(it, n, size, opts).outputs()
+}
+
+// fanout hands out the elements from a single source to several
+// consumers, each of which sees every element. Elements are pulled
+// from the source by whichever consumer gets ahead of the others,
+// and held until every consumer has moved past them.
+//
+// Positions are counted from the start of the source. Elements in
+// [memStart, memStart+len(buf)) are held in memory and, when
+// spilling, those in [spillStart, memStart) are held on disk.
+type fanout[T any] struct {
+	splitter[T]
+
+	size uint64
+	opts BroadcastOptions[T]
+
+	positions []uint64
+
+	buf      []Elem[T]
+	memStart uint64
+
+	spill      *os.File
+	spillStart uint64
+	encode     func(T) error
+	spillErrs  map[uint64]error
+	spillErr   error
+	readers    map[int]*spillReader[T]
+}
+
+// spillReader reads spilled values back for a single consumer.
+type spillReader[T any] struct {
+	file   *os.File
+	decode func() (T, error)
+	pos    uint64
+}
+
+// newFanout creates a fanout with n consumers, a size of zero means
+// the buffer is unbounded.
+func newFanout[T any](it *Iter[T], n uint32, size uint64, opts BroadcastOptions[T]) *fanout[T] {
+	f := &fanout[T]{
+		size:      size,
+		opts:      opts,
+		positions: make([]uint64, n),
+		spillErrs: map[uint64]error{},
+		readers:   map[int]*spillReader[T]{},
+	}
+	f.init(it, int(n))
+
+	return f
+}
+
+func (f *fanout[T]) outputs() []*Iter[T] {
+	// A closed consumer no longer holds anything back, so trimming
+	// lets go of whatever it was the last to need.
+	return f.splitter.outputs(f.next, func(int) {
+		f.trim()
+	})
+}
+
+func (f *fanout[T]) next(i int) (Elem[T], bool) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	for {
+		if f.closed[i] {
+			return DoneElem[T]()
+		}
+
+		pos := f.positions[i]
+
+		if pos < f.memStart {
+			return f.readSpill(i)
+		}
+
+		if pos < f.memStart+uint64(len(f.buf)) {
+			elem := f.buf[pos-f.memStart]
+			f.positions[i]++
+			f.trim()
+			return elem, true
+		}
+
+		if f.exhausted {
+			return DoneElem[T]()
+		}
+
+		if f.pulling {
+			f.cond.Wait()
+			continue
+		}
+
+		if f.size > 0 && uint64(len(f.buf)) >= f.size && f.opts.OnLag == BlockOnLag {
+			f.cond.Wait()
+			continue
+		}
+
+		elem, valid := f.pull()
+		if !valid {
+			f.exhausted = true
+			continue
+		}
+
+		f.buf = append(f.buf, elem)
+
+		if f.size > 0 && uint64(len(f.buf)) > f.size {
+			switch f.opts.OnLag {
+			case DropOnLag:
+				for j := range f.positions {
+					f.positions[j] = max(f.positions[j], f.memStart+1)
+				}
+				f.trim()
+			case SpillOnLag:
+				// Once spilling fails, we give up on it and hold
+				// values in memory instead, rather than lose them.
+				if f.spillErr != nil {
+					break
+				}
+
+				f.spillErr = f.spillOldest()
+				if f.spillErr != nil {
+					return ErrElem[T](fmt.Errorf("broadcast spill error: %w", f.spillErr))
+				}
+			}
+		}
+	}
+}
+
+// trim lets go of elements that every open consumer has moved past,
+// and wakes up anyone who was waiting for room in the buffer. It
+// must be called with the lock held.
+func (f *fanout[T]) trim() {
+	least := f.memStart + uint64(len(f.buf))
+	for j, pos := range f.positions {
+		if !f.closed[j] {
+			least = min(least, pos)
+		}
+	}
+
+	if least > f.memStart {
+		clear(f.buf[:least-f.memStart])
+		f.buf = f.buf[least-f.memStart:]
+		f.memStart = least
+	}
+
+	if f.spill != nil && least >= f.memStart {
+		f.removeSpill()
+	}
+
+	f.cond.Broadcast()
+}
+
+// spillOldest moves the oldest element in memory to the spill file,
+// creating the file if necessary. It must be called with the lock
+// held.
+func (f *fanout[T]) spillOldest() error {
+	if f.spill == nil {
+		file, err := os.CreateTemp(f.opts.TempDir, "funky-broadcast-*")
+		if err != nil {
+			return err
+		}
+
+		f.spill = file
+		f.spillStart = f.memStart
+		f.encode = f.opts.Codec.Encoder(file)
+	}
+
+	elem := f.buf[0]
+	err := f.encode(elem.val)
+	if err != nil {
+		return err
+	}
+
+	if elem.err != nil {
+		f.spillErrs[f.memStart] = elem.err
+	}
+
+	f.buf[0] = Elem[T]{}
+	f.buf = f.buf[1:]
+	f.memStart++
+
+	return nil
+}
+
+// readSpill reads the next spilled element for the i-th consumer.
+// It must be called with the lock held.
+func (f *fanout[T]) readSpill(i int) (Elem[T], bool) {
+	pos := f.positions[i]
+	f.positions[i]++
+	defer f.trim()
+
+	r, ok := f.readers[i]
+	if !ok {
+		file, err := os.Open(f.spill.Name())
+		if err != nil {
+			return ErrElem[T](fmt.Errorf("broadcast spill error: %w", err))
+		}
+
+		r = &spillReader[T]{
+			file:   file,
+			decode: f.opts.Codec.Decoder(file),
+			pos:    f.spillStart,
+		}
+		f.readers[i] = r
+	}
+
+	// Catch up to the consumer's position, which is only necessary
+	// the first time a consumer reads from a new spill file.
+	for r.pos < pos {
+		_, err := r.decode()
+		r.pos++
+
+		if err != nil {
+			return ErrElem[T](spillReadError(err))
+		}
+	}
+
+	val, err := r.decode()
+	r.pos++
+
+	if err != nil {
+		return ErrElem[T](spillReadError(err))
+	}
+
+	return Elem[T]{val: val, err: f.spillErrs[pos]}, true
+}
+
+// spillReadError wraps a problem reading a spill file. Values are
+// only read once they've been written, so running out is unexpected.
+func spillReadError(err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+
+	return fmt.Errorf("broadcast spill error: %w", err)
+}
+
+// removeSpill deletes the spill file once nobody needs it anymore.
+// It must be called with the lock held.
+func (f *fanout[T]) removeSpill() {
+	for _, r := range f.readers {
+		_ = r.file.Close()
+	}
+
+	_ = f.spill.Close()
+	_ = os.Remove(f.spill.Name())
+
+	f.spill = nil
+	f.encode = nil
+	clear(f.spillErrs)
+	clear(f.readers)
+}
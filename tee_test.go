@@ -0,0 +1,161 @@
+package funky
+
+import (
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestTee(t *testing.T) {
+	t.Run("should give every output every value", func(t *testing.T) {
+		outputs := Tee(FromVals(1, 2, 3), 2)
+		assert.Equal(t, 2, len(outputs))
+
+		assertValues(t, outputs[0], []int{1, 2, 3}, true)
+		assertValues(t, outputs[1], []int{1, 2, 3}, true)
+	})
+
+	t.Run("should allow outputs to be read in turns", func(t *testing.T) {
+		outputs := Tee(FromVals(1, 2, 3), 2)
+
+		assertValues(t, outputs[0], []int{1}, false)
+		assertValues(t, outputs[1], []int{1, 2}, false)
+		assertValues(t, outputs[0], []int{2, 3}, true)
+		assertValues(t, outputs[1], []int{3}, true)
+	})
+
+	t.Run("should pass through errors", func(t *testing.T) {
+		outputs := Tee(makeMixed(), 2)
+
+		for _, output := range outputs {
+			assertValues(t, output, []int{1}, false)
+			elem, valid := output.Next()
+			assert.True(t, valid)
+			assert.Error(t, elem.err)
+			assertValues(t, output, []int{2}, true)
+		}
+	})
+
+	t.Run("should allow concurrent outputs", func(t *testing.T) {
+		outputs := Tee(makeFinite(1000), 4)
+
+		var wg sync.WaitGroup
+		for _, output := range outputs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Equal(t, 1000, len(output.ToSlice(2000)))
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("should close the source once every output is closed", func(t *testing.T) {
+		source := makeInfinite()
+		outputs := Tee(source, 2)
+
+		assertValues(t, outputs[0], []int{0, 1}, false)
+		outputs[0].Close()
+
+		_, valid := outputs[0].Next()
+		assert.False(t, valid)
+
+		select {
+		case <-source.Done():
+			t.Fatal("source closed too early")
+		default:
+		}
+
+		assertValues(t, outputs[1], []int{0, 1, 2}, false)
+		outputs[1].Close()
+		assertClosed(t, source)
+	})
+}
+
+func TestBroadcast(t *testing.T) {
+	t.Run("should block until the slowest output catches up", func(t *testing.T) {
+		outputs := Broadcast(makeFinite(100), 2, BroadcastOptions[int]{Size: 2})
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, 100, len(outputs[0].ToSlice(200)))
+		}()
+
+		assert.Equal(t, 100, len(outputs[1].ToSlice(200)))
+		wg.Wait()
+	})
+
+	t.Run("should stop blocking when the slowest output is closed", func(t *testing.T) {
+		outputs := Broadcast(makeFinite(10), 2, BroadcastOptions[int]{Size: 2})
+
+		assertValues(t, outputs[0], []int{0, 1}, false)
+		outputs[1].Close()
+		assertValues(t, outputs[0], []int{2, 3, 4, 5, 6, 7, 8, 9}, true)
+	})
+
+	t.Run("should drop values for the slowest output", func(t *testing.T) {
+		outputs := Broadcast(makeFinite(5), 2, BroadcastOptions[int]{
+			Size:  2,
+			OnLag: DropOnLag,
+		})
+
+		assertValues(t, outputs[0], []int{0, 1, 2, 3, 4}, true)
+		assertValues(t, outputs[1], []int{3, 4}, true)
+	})
+
+	t.Run("should spill values for the slowest output", func(t *testing.T) {
+		dir := t.TempDir()
+		outputs := Broadcast(makeMixed(), 2, BroadcastOptions[int]{
+			Size:    1,
+			OnLag:   SpillOnLag,
+			TempDir: dir,
+		})
+
+		assertValues(t, outputs[0], []int{1}, false)
+		elem, _ := outputs[0].Next()
+		assert.Error(t, elem.err)
+		assertValues(t, outputs[0], []int{2}, true)
+
+		assertValues(t, outputs[1], []int{1}, false)
+		elem, _ = outputs[1].Next()
+		assert.EqualError(t, elem.err, "error")
+		assertValues(t, outputs[1], []int{2}, true)
+
+		entries, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, len(entries))
+	})
+
+	t.Run("should spill with another codec", func(t *testing.T) {
+		outputs := Broadcast(makeFinite(100), 3, BroadcastOptions[int]{
+			Size:    10,
+			OnLag:   SpillOnLag,
+			Codec:   JSONCodec[int]{},
+			TempDir: t.TempDir(),
+		})
+
+		assertValues(t, outputs[0], []int{0, 1, 2}, false)
+		assert.Equal(t, 100, len(outputs[2].ToSlice(200)))
+		assert.Equal(t, 97, len(outputs[0].ToSlice(200)))
+		assert.Equal(t, 100, len(outputs[1].ToSlice(200)))
+	})
+
+	t.Run("should report spill failures and carry on", func(t *testing.T) {
+		outputs := Broadcast(makeFinite(5), 2, BroadcastOptions[int]{
+			Size:    1,
+			OnLag:   SpillOnLag,
+			TempDir: "/this/does/not/exist",
+		})
+
+		assertValues(t, outputs[0], []int{0}, false)
+		elem, valid := outputs[0].Next()
+		assert.True(t, valid)
+		assert.Error(t, elem.err)
+		assertValues(t, outputs[0], []int{1, 2, 3, 4}, true)
+		assertValues(t, outputs[1], []int{0, 1, 2, 3, 4}, true)
+	})
+}
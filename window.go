@@ -0,0 +1,333 @@
+package funky
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Sliding produces overlapping windows of size values, starting a
+// new window every step values. Like Chunk, errors from a window's
+// elements are joined together and passed along with the window.
+//
+// The final window may be shorter than size if the iterator ends
+// before it is filled, but only if it contains values that weren't
+// already part of an earlier window. This means that Sliding with
+// a step equal to size is the same as Chunk. If step is larger than
+// size, the values in between windows are skipped, but their errors
+// are joined into the next window. A step of zero is treated as one.
+//
+// For example (in pseudocode):
+//
+//	Sliding({1, 2, 3, 4}, 3, 1) -> {{1, 2, 3}, {2, 3, 4}}
+func Sliding[T any](it *Iter[T], size, step uint64) *Iter[[]T] {
+	if step == 0 {
+		step = 1
+	}
+
+	var window []Elem[T]
+	var skipped []error
+	started := false
+	var lock sync.Mutex
+
+	return &Iter[[]T]{
+		next: func() (Elem[[]T], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			if size == 0 {
+				return DoneElem[[]T]()
+			}
+
+			// Slide the window along, skipping any values that fall
+			// between this window and the last one, but holding on
+			// to their errors.
+			if started {
+				skip := step
+				if skip > uint64(len(window)) {
+					for range skip - uint64(len(window)) {
+						elem, valid := it.Next()
+						if !valid {
+							break
+						}
+
+						if elem.err != nil {
+							skipped = append(skipped, elem.err)
+						}
+					}
+
+					skip = uint64(len(window))
+				}
+
+				window = window[skip:]
+			}
+
+			started = true
+
+			fresh := 0
+			for uint64(len(window)) < size {
+				elem, valid := it.Next()
+				if !valid {
+					break
+				}
+
+				window = append(window, elem)
+				fresh++
+			}
+
+			// Every value in the window has already been seen, so
+			// the iterator must be exhausted.
+			if fresh == 0 {
+				size = 0
+
+				if len(skipped) > 0 {
+					return ErrElem[[]T](errors.Join(skipped...))
+				}
+
+				return DoneElem[[]T]()
+			}
+
+			out := joinWindow(window, skipped)
+			skipped = nil
+
+			return out, true
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// TumblingTime produces windows of values whose timestamps, given
+// by ts, fall into the same span of time. Spans are width long and
+// aligned to the zero time, so, for example, a width of one minute
+// produces windows that begin on the minute.
+//
+// Values must arrive in order of their timestamps, or at least in
+// order of their spans, because a window is produced as soon as a
+// value from a later span arrives. Error elements can't be given a
+// timestamp, so their errors are joined into the next window that
+// is produced, and their values are dropped. The width must be
+// positive.
+func TumblingTime[T any](it *Iter[T], ts KeyFunc[T, time.Time], width time.Duration) *Iter[[]T] {
+	return SlidingTime(it, ts, width, width)
+}
+
+// SlidingTime is like TumblingTime, but the windows overlap. A new
+// window starts every step, and each window includes the values
+// with timestamps from its start up to, but not including, its
+// start plus width. Windows without any values are skipped. If step
+// is larger than width, values that fall between windows don't
+// belong to any of them, so they are dropped. Both width and step
+// must be positive.
+//
+// For example, a width of five minutes and a step of one minute
+// produces a five minute moving window that advances one minute at
+// a time.
+func SlidingTime[T any](it *Iter[T], ts KeyFunc[T, time.Time], width, step time.Duration) *Iter[[]T] {
+	if width <= 0 {
+		panic("funky: window width must be positive")
+	}
+
+	if step <= 0 {
+		panic("funky: window step must be positive")
+	}
+
+	type stamped struct {
+		at   time.Time
+		elem Elem[T]
+	}
+
+	// Values that may still belong to a window that hasn't been
+	// produced, in order, along with their timestamps.
+	var buffered []stamped
+	var start time.Time
+
+	// firstStart finds the earliest window that contains t, or, if
+	// t falls between windows, the first one after it.
+	firstStart := func(t time.Time) time.Time {
+		s := t.Add(-width).Truncate(step)
+		for !s.Add(width).After(t) {
+			s = s.Add(step)
+		}
+
+		return s
+	}
+
+	// flushBefore produces each window that ends at or before t,
+	// pass the zero time to flush everything.
+	var queue []Elem[[]T]
+	var errs []error
+	flushBefore := func(t time.Time) {
+		for len(buffered) > 0 {
+			end := start.Add(width)
+			if !t.IsZero() && end.After(t) {
+				return
+			}
+
+			var window []Elem[T]
+			for _, s := range buffered {
+				if !s.at.Before(end) {
+					break
+				}
+
+				if s.at.Before(start) {
+					continue
+				}
+
+				window = append(window, s.elem)
+			}
+
+			if len(window) > 0 {
+				queue = append(queue, joinWindow(window, errs))
+				errs = nil
+			}
+
+			start = start.Add(step)
+
+			dropped := 0
+			for dropped < len(buffered) && buffered[dropped].at.Before(start) {
+				dropped++
+			}
+			buffered = buffered[dropped:]
+
+			// Jump over empty windows rather than stepping through
+			// them one by one.
+			if len(buffered) > 0 && buffered[0].at.Sub(start) >= width {
+				start = firstStart(buffered[0].at)
+			}
+		}
+	}
+
+	exhausted := false
+	var lock sync.Mutex
+
+	return &Iter[[]T]{
+		next: func() (Elem[[]T], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			for len(queue) == 0 && !exhausted {
+				elem, valid := it.Next()
+				if !valid {
+					exhausted = true
+					flushBefore(time.Time{})
+
+					if len(errs) > 0 {
+						queue = append(queue, Elem[[]T]{err: errors.Join(errs...)})
+						errs = nil
+					}
+
+					break
+				}
+
+				if elem.err != nil {
+					errs = append(errs, elem.err)
+					continue
+				}
+
+				at := ts(elem.val)
+				flushBefore(at)
+
+				// A value between windows doesn't belong to any
+				// of them.
+				first := firstStart(at)
+				if first.After(at) {
+					continue
+				}
+
+				if len(buffered) == 0 {
+					start = first
+				}
+
+				buffered = append(buffered, stamped{at, elem})
+			}
+
+			if len(queue) == 0 {
+				return DoneElem[[]T]()
+			}
+
+			window := queue[0]
+			queue = queue[1:]
+
+			return window, true
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// Session produces windows of values separated by periods of
+// inactivity. A window ends when the next value's timestamp, given
+// by ts, is more than gap after the timestamp of the value before
+// it. Values must arrive in order of their timestamps.
+//
+// Error elements can't be given a timestamp, so their errors are
+// joined into the window that is being assembled when they arrive,
+// and their values are dropped.
+func Session[T any](it *Iter[T], ts KeyFunc[T, time.Time], gap time.Duration) *Iter[[]T] {
+	var window []Elem[T]
+	var errs []error
+	var last time.Time
+	var lock sync.Mutex
+
+	return &Iter[[]T]{
+		next: func() (Elem[[]T], bool) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			for {
+				elem, valid := it.Next()
+				if !valid {
+					if len(window) == 0 && len(errs) == 0 {
+						return DoneElem[[]T]()
+					}
+
+					out := joinWindow(window, errs)
+					window, errs = nil, nil
+
+					return out, true
+				}
+
+				if elem.err != nil {
+					errs = append(errs, elem.err)
+					continue
+				}
+
+				at := ts(elem.val)
+				if len(window) > 0 && at.Sub(last) > gap {
+					out := joinWindow(window, errs)
+					window, errs = []Elem[T]{elem}, nil
+					last = at
+
+					return out, true
+				}
+
+				window = append(window, elem)
+				last = at
+			}
+		},
+		close: func() {
+			it.Close()
+		},
+	}
+}
+
+// joinWindow assembles a window from its elements, joining their
+// errors, along with any extra errors, the way Chunk does.
+func joinWindow[T any](window []Elem[T], extra []error) Elem[[]T] {
+	vals := make([]T, len(window))
+	errs := errors.Join(extra...)
+	for i, elem := range window {
+		vals[i] = elem.val
+		if elem.err != nil {
+			errs = errors.Join(errs, elem.err)
+		}
+	}
+
+	return Elem[[]T]{
+		val: vals,
+		err: errs,
+	}
+}
@@ -0,0 +1,192 @@
+package funky
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestSliding(t *testing.T) {
+	t.Run("should produce overlapping windows", func(t *testing.T) {
+		it := Sliding(makeFinite(5), 3, 1)
+		assertValues(t, it, [][]int{{0, 1, 2}, {1, 2, 3}, {2, 3, 4}}, true)
+	})
+
+	t.Run("should match Chunk when step equals size", func(t *testing.T) {
+		it := Sliding(makeFinite(5), 2, 2)
+		assertValues(t, it, [][]int{{0, 1}, {2, 3}, {4}}, true)
+	})
+
+	t.Run("should skip values between windows", func(t *testing.T) {
+		it := Sliding(makeFinite(7), 2, 3)
+		assertValues(t, it, [][]int{{0, 1}, {3, 4}, {6}}, true)
+	})
+
+	t.Run("should produce a short window from a short iterator", func(t *testing.T) {
+		it := Sliding(makeFinite(2), 3, 1)
+		assertValues(t, it, [][]int{{0, 1}}, true)
+	})
+
+	t.Run("should join errors within each window", func(t *testing.T) {
+		it := Sliding(makeMixed(), 2, 1)
+
+		first, valid := it.Next()
+		assert.True(t, valid)
+		assert.Equal(t, []int{1, 0}, first.val)
+		assert.Error(t, first.err)
+
+		second, valid := it.Next()
+		assert.True(t, valid)
+		assert.Equal(t, []int{0, 2}, second.val)
+		assert.Error(t, second.err)
+
+		_, valid = it.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should keep errors from skipped values", func(t *testing.T) {
+		it := Sliding(makeMixed(), 1, 2)
+
+		first, valid := it.Next()
+		assert.True(t, valid)
+		assert.Equal(t, []int{1}, first.val)
+		assert.NoError(t, first.err)
+
+		second, valid := it.Next()
+		assert.True(t, valid)
+		assert.Equal(t, []int{2}, second.val)
+		assert.Error(t, second.err)
+
+		_, valid = it.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should keep errors skipped at the end", func(t *testing.T) {
+		it := Sliding(makeFrom([]Elem[int]{
+			{val: 1},
+			{err: errors.New("error")},
+		}), 1, 2)
+
+		first, valid := it.Next()
+		assert.True(t, valid)
+		assert.Equal(t, []int{1}, first.val)
+		assert.NoError(t, first.err)
+
+		second, valid := it.Next()
+		assert.True(t, valid)
+		assert.Error(t, second.err)
+
+		_, valid = it.Next()
+		assert.False(t, valid)
+	})
+
+	t.Run("should close the source", func(t *testing.T) {
+		src := makeFinite(3)
+		Sliding(src, 2, 1).Close()
+		assertClosed(t, src)
+	})
+}
+
+// minutes produces times that many minutes after a fixed point, so
+// that windows of whole minutes line up with them.
+func minutes(ms ...int) *Iter[time.Time] {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	times := make([]time.Time, len(ms))
+	for i, m := range ms {
+		times[i] = base.Add(time.Duration(m) * time.Minute)
+	}
+
+	return FromSlice(times)
+}
+
+// minuteOf maps times produced by minutes back to their offsets.
+func minuteOf(ts []time.Time) []int {
+	ms := make([]int, len(ts))
+	for i, t := range ts {
+		ms[i] = t.Minute()
+	}
+
+	return ms
+}
+
+func itself(t time.Time) time.Time {
+	return t
+}
+
+func TestTumblingTime(t *testing.T) {
+	t.Run("should group values into spans", func(t *testing.T) {
+		it := Apply(TumblingTime(minutes(0, 1, 4, 5, 12), itself, 5*time.Minute), func(ts []time.Time) ([]int, error) {
+			return minuteOf(ts), nil
+		})
+		assertValues(t, it, [][]int{{0, 1, 4}, {5}, {12}}, true)
+	})
+
+	t.Run("should attach errors to the next window", func(t *testing.T) {
+		base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		it := TumblingTime(makeFrom([]Elem[int]{
+			{val: 0},
+			{err: errors.New("error")},
+			{val: 10},
+		}), func(v int) time.Time {
+			return base.Add(time.Duration(v) * time.Minute)
+		}, 5*time.Minute)
+
+		first, valid := it.Next()
+		assert.True(t, valid)
+		assert.Equal(t, []int{0}, first.val)
+		assert.Error(t, first.err)
+
+		assertValues(t, it, [][]int{{10}}, true)
+	})
+
+	t.Run("should panic on a width that isn't positive", func(t *testing.T) {
+		assert.Panics(t, func() {
+			TumblingTime(minutes(0), itself, 0)
+		})
+	})
+}
+
+func TestSlidingTime(t *testing.T) {
+	t.Run("should produce overlapping windows", func(t *testing.T) {
+		it := Apply(SlidingTime(minutes(0, 1, 2, 10), itself, 2*time.Minute, time.Minute), func(ts []time.Time) ([]int, error) {
+			return minuteOf(ts), nil
+		})
+		assertValues(t, it, [][]int{{0}, {0, 1}, {1, 2}, {2}, {10}, {10}}, true)
+	})
+
+	t.Run("should drop values between windows", func(t *testing.T) {
+		it := Apply(SlidingTime(minutes(0, 3, 5, 9, 10, 14), itself, 2*time.Minute, 5*time.Minute), func(ts []time.Time) ([]int, error) {
+			return minuteOf(ts), nil
+		})
+		assertValues(t, it, [][]int{{0}, {5}, {10}}, true)
+	})
+
+	t.Run("should panic on a width that isn't positive", func(t *testing.T) {
+		assert.Panics(t, func() {
+			SlidingTime(minutes(0), itself, 0, time.Minute)
+		})
+	})
+
+	t.Run("should panic on a step that isn't positive", func(t *testing.T) {
+		assert.Panics(t, func() {
+			SlidingTime(minutes(0), itself, time.Minute, 0)
+		})
+	})
+}
+
+func TestSession(t *testing.T) {
+	t.Run("should split on inactivity", func(t *testing.T) {
+		it := Apply(Session(minutes(0, 1, 3, 10, 11, 20), itself, 2*time.Minute), func(ts []time.Time) ([]int, error) {
+			return minuteOf(ts), nil
+		})
+		assertValues(t, it, [][]int{{0, 1, 3}, {10, 11}, {20}}, true)
+	})
+
+	t.Run("should handle an empty iterator", func(t *testing.T) {
+		it := Session(minutes(), itself, time.Minute)
+		assertValues(t, it, [][]time.Time{}, true)
+	})
+}
@@ -0,0 +1,132 @@
+package funky
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WriteOptions configures WriteLines, WriteCSV and WriteJSONLines.
+type WriteOptions[T any] struct {
+	// OnError decides what happens to elements that carry an
+	// error. By default, writing stops and the error is returned.
+	// Under CollectErrors, writing carries on and the errors are
+	// returned, joined together, at the end.
+	OnError ErrorPolicy
+
+	// DeadLetters receives the elements that carry an error under
+	// DeadLetterErrors, in which case it must be set. Dead letters
+	// are numbered by their position among all of the elements, and
+	// their stage is left empty. If the sink fails, writing stops
+	// and both errors are returned.
+	DeadLetters DeadLetterSink[T]
+}
+
+// WriteSummary describes what happened during a write.
+type WriteSummary struct {
+	// Written is the number of values that were written.
+	Written uint64
+
+	// Errors is the number of elements that carried an error,
+	// whether or not writing stopped because of them.
+	Errors uint64
+}
+
+// WriteLines drains the iterator into w, writing each value on
+// its own line, formatted as it would be by fmt.Println. Output is
+// buffered, and flushed before the function returns, even if it
+// returns an error.
+//
+// The returned error is either an element error, if the options
+// call for writing to stop, or an error from w.
+func WriteLines[T any](it *Iter[T], w io.Writer, opts WriteOptions[T]) (WriteSummary, error) {
+	bw := bufio.NewWriter(w)
+
+	return write(it, opts, func(v T) error {
+		_, err := fmt.Fprintln(bw, v)
+		return err
+	}, bw.Flush)
+}
+
+// WriteCSV drains the iterator into w, writing each slice as a
+// single CSV record. It behaves like WriteLines otherwise.
+func WriteCSV(it *Iter[[]string], w io.Writer, opts WriteOptions[[]string]) (WriteSummary, error) {
+	cw := csv.NewWriter(w)
+
+	return write(it, opts, cw.Write, func() error {
+		cw.Flush()
+		return cw.Error()
+	})
+}
+
+// WriteJSONLines drains the iterator into w, encoding each value
+// as JSON on its own line. It behaves like WriteLines otherwise.
+func WriteJSONLines[T any](it *Iter[T], w io.Writer, opts WriteOptions[T]) (WriteSummary, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	return write(it, opts, func(v T) error {
+		return enc.Encode(v)
+	}, bw.Flush)
+}
+
+// write drains the iterator, passing each value to put and handling
+// element errors according to the options, then calls flush. Flush
+// is called even when writing stops early, so that whatever was
+// written before the error isn't lost.
+func write[T any](it *Iter[T], opts WriteOptions[T], put func(T) error, flush func() error) (WriteSummary, error) {
+	if opts.OnError == DeadLetterErrors && opts.DeadLetters == nil {
+		panic("funky: DeadLetterErrors requires a dead letter sink")
+	}
+
+	var summary WriteSummary
+	var errs []error
+	var seq uint64
+
+	// fail flushes what has been written so far, and returns err
+	// along with any error from the flush.
+	fail := func(err error) (WriteSummary, error) {
+		if flushErr := flush(); flushErr != nil {
+			err = errors.Join(err, fmt.Errorf("write error: %w", flushErr))
+		}
+
+		return summary, err
+	}
+
+	for elem, valid := it.Next(); valid; elem, valid = it.Next() {
+		seq++
+
+		if elem.err != nil {
+			summary.Errors++
+
+			switch opts.OnError {
+			case SkipErrors:
+			case CollectErrors:
+				errs = append(errs, elem.err)
+			case DeadLetterErrors:
+				if err := putDeadLetter(opts.DeadLetters, "", seq-1, elem.val, elem.err); err != nil {
+					return fail(err)
+				}
+			default:
+				return fail(elem.err)
+			}
+
+			continue
+		}
+
+		if err := put(elem.val); err != nil {
+			return fail(fmt.Errorf("write error: %w", err))
+		}
+
+		summary.Written++
+	}
+
+	if err := flush(); err != nil {
+		return summary, fmt.Errorf("write error: %w", err)
+	}
+
+	return summary, errors.Join(errs...)
+}
@@ -0,0 +1,140 @@
+package funky
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestWriteLines(t *testing.T) {
+	t.Run("should write each value on a line", func(t *testing.T) {
+		var sb strings.Builder
+		summary, err := WriteLines(makeFinite(3), &sb, WriteOptions[int]{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "0\n1\n2\n", sb.String())
+		assert.Equal(t, WriteSummary{Written: 3}, summary)
+	})
+
+	t.Run("should stop at the first error by default", func(t *testing.T) {
+		var sb strings.Builder
+		summary, err := WriteLines(makeMixed(), &sb, WriteOptions[int]{})
+		assert.Error(t, err)
+
+		assert.Equal(t, "1\n", sb.String())
+		assert.Equal(t, WriteSummary{Written: 1, Errors: 1}, summary)
+	})
+
+	t.Run("should skip errors", func(t *testing.T) {
+		var sb strings.Builder
+		summary, err := WriteLines(makeMixed(), &sb, WriteOptions[int]{OnError: SkipErrors})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "1\n2\n", sb.String())
+		assert.Equal(t, WriteSummary{Written: 2, Errors: 1}, summary)
+	})
+
+	t.Run("should collect errors", func(t *testing.T) {
+		var sb strings.Builder
+		summary, err := WriteLines(fromElems([]Elem[int]{
+			{err: errors.New("first")},
+			{val: 1},
+			{err: errors.New("second")},
+		}), &sb, WriteOptions[int]{OnError: CollectErrors})
+		assert.EqualError(t, err, "first\nsecond")
+
+		assert.Equal(t, "1\n", sb.String())
+		assert.Equal(t, WriteSummary{Written: 1, Errors: 2}, summary)
+	})
+
+	t.Run("should dead letter errors", func(t *testing.T) {
+		var sb strings.Builder
+		var sink MemorySink[int]
+		summary, err := WriteLines(makeTwoErrors(), &sb, WriteOptions[int]{
+			OnError:     DeadLetterErrors,
+			DeadLetters: &sink,
+		})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "1\n2\n3\n", sb.String())
+		assert.Equal(t, WriteSummary{Written: 3, Errors: 2}, summary)
+
+		letters := sink.Letters()
+		assert.Equal(t, 2, len(letters))
+		assert.Equal(t, uint64(1), letters[0].Seq)
+		assert.EqualError(t, letters[0].Err, "first")
+		assert.Equal(t, uint64(3), letters[1].Seq)
+		assert.Equal(t, 20, letters[1].Input)
+	})
+
+	t.Run("should stop when the dead letter sink fails", func(t *testing.T) {
+		var sb strings.Builder
+		summary, err := WriteLines(makeMixed(), &sb, WriteOptions[int]{
+			OnError: DeadLetterErrors,
+			DeadLetters: SinkFunc[int](func(DeadLetter[int]) error {
+				return errWrite
+			}),
+		})
+		assert.IsError(t, err, errWrite)
+
+		assert.Equal(t, "1\n", sb.String())
+		assert.Equal(t, WriteSummary{Written: 1, Errors: 1}, summary)
+	})
+
+	t.Run("should require a dead letter sink", func(t *testing.T) {
+		assert.Panics(t, func() {
+			_, _ = WriteLines(makeMixed(), io.Discard, WriteOptions[int]{OnError: DeadLetterErrors})
+		})
+	})
+
+	t.Run("should report writer errors", func(t *testing.T) {
+		_, err := WriteLines(makeFinite(1), &failingWriter{}, WriteOptions[int]{})
+		assert.IsError(t, err, errWrite)
+	})
+}
+
+func TestWriteCSV(t *testing.T) {
+	t.Run("should write each record", func(t *testing.T) {
+		var sb strings.Builder
+		summary, err := WriteCSV(FromVals([]string{"a", "b"}, []string{"1", "2,3"}), &sb, WriteOptions[[]string]{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "a,b\n1,\"2,3\"\n", sb.String())
+		assert.Equal(t, WriteSummary{Written: 2}, summary)
+	})
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	t.Run("should write each value as JSON", func(t *testing.T) {
+		type record struct {
+			Name string `json:"name"`
+		}
+
+		var sb strings.Builder
+		summary, err := WriteJSONLines(FromVals(record{"a"}, record{"b"}), &sb, WriteOptions[record]{})
+		assert.NoError(t, err)
+
+		assert.Equal(t, "{\"name\":\"a\"}\n{\"name\":\"b\"}\n", sb.String())
+		assert.Equal(t, WriteSummary{Written: 2}, summary)
+	})
+
+	t.Run("should flush what was written before a value fails", func(t *testing.T) {
+		var sb strings.Builder
+		summary, err := WriteJSONLines(FromVals[any](1, 2, make(chan int)), &sb, WriteOptions[any]{})
+		assert.Error(t, err)
+
+		assert.Equal(t, "1\n2\n", sb.String())
+		assert.Equal(t, WriteSummary{Written: 2}, summary)
+	})
+}
+
+var errWrite = errors.New("write error")
+
+type failingWriter struct{}
+
+func (w *failingWriter) Write(_ []byte) (int, error) {
+	return 0, errWrite
+}